@@ -3,7 +3,9 @@ package adstxt
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"runtime"
 	"sync"
 	"time"
@@ -12,67 +14,107 @@ import (
 // Get crawl and parse Ads.txt file from remote host based on Ads.txt Specification Version 1.0.1
 // https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
 func Get(req *Request) (*Response, error) {
-	c := newCrawler()
+	return defaultCrawler.GetContext(context.Background(), req)
+}
 
-	// send Ads.txt request to remote server and parse response
-	for {
-		res, err := c.sendRequest(req)
-		if err != nil {
-			return nil, err
-		}
-		defer res.Body.Close()
-
-		// handle Ads.txt response
-		switch {
-		// the server response indicates redirect (301, 302, 307 status codes), follow redirect and read Ads.txt
-		// file from the source of the redirect
-		case 300 <= res.StatusCode && res.StatusCode < 400:
-			redirect, err := c.handleRedirect(req, res)
-			if err != nil {
-				return nil, err
-			}
-			req.URL = redirect
-		// client error in remote server response
-		case 400 <= res.StatusCode && res.StatusCode < 500:
-			return nil, fmt.Errorf(errHTTPClientError, res.Status, req.Domain, req.URL)
-		// the server response indicates Success (HTTP Status Code 200): read and parse the content of the Ads.txt file
-		case res.StatusCode == 200:
-			body, err := c.readBody(req, res)
-			if err != nil {
-				return nil, err
-			}
+// GetContext crawl and parse Ads.txt file from remote host, the provided context is used to cancel the request (including
+// following redirects and reading the response body) or to set a deadline for it
+func GetContext(ctx context.Context, req *Request) (*Response, error) {
+	return defaultCrawler.GetContext(ctx, req)
+}
 
-			// return new response
-			records, err := ParseBody(body)
-			if err != nil {
-				return nil, err
-			}
+// GetMultiple crawl and parse multiple Ads.txt files from remote hosts based on Ads.txt Specification Version 1.0.1
+// https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
+func GetMultiple(req []*Request, h Handler) {
+	defaultCrawler.GetMultipleContext(context.Background(), req, h)
+}
 
-			// Ads.txt response
-			r := &Response{
-				Request: req,
-				Records: records,
-				// Ads.txt file default expiration date is set to 7 days (secion 3.6 EXPIRATION of IAB Ads.txt specification)
-				Expires: time.Now().UTC().AddDate(0, 0, 7),
-			}
+// GetMultipleContext crawl and parse multiple Ads.txt files from remote hosts. Once the provided context is cancelled (or its
+// deadline expires) all running requests are aborted, and the requests that did not start yet are passed to the handler with
+// the context error
+func GetMultipleContext(ctx context.Context, req []*Request, h Handler) {
+	defaultCrawler.GetMultipleContext(ctx, req, h)
+}
 
-			// parse Ads.txt expiration date from response (else default expiration time is used)
-			expires, err := c.parseExpires(res)
-			if err == nil {
-				r.Expires = expires
-			}
+// Get crawl and parse Ads.txt file from remote host using the crawler settings
+func (c *Crawler) Get(req *Request) (*Response, error) {
+	return c.GetContext(context.Background(), req)
+}
 
-			return r, nil
-		// un known HTTP status
-		default:
-			return nil, fmt.Errorf(errHTTPGeneralError, res.Status, req.Domain, req.URL)
+// GetContext crawl and parse Ads.txt file from remote host using the crawler settings, the provided context is used to
+// cancel the request or to set a deadline for it
+func (c *Crawler) GetContext(ctx context.Context, req *Request) (*Response, error) {
+	// return cached Ads.txt file that has not expired yet, else send conditional request for it
+	var cached *CacheEntry
+	if c.cache != nil {
+		if e, ok := c.cache.Get(cacheKey(req)); ok {
+			if time.Now().Before(e.Response.Expires) {
+				r := *e.Response
+				r.Request = req
+				r.CacheStatus = CacheHit
+				return &r, nil
+			}
+			cached = e
+			ctx = withConditional(ctx, e)
 		}
 	}
+
+	// send Ads.txt request to remote server, follow redirects and read Ads.txt file content
+	d, err := c.download(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// the remote host confirmed the cached Ads.txt file was not modified
+	if d.notModified {
+		r := c.revalidated(cached, d)
+		r.Request = req
+		c.cache.Set(cacheKey(req), newCacheEntry(r))
+		return r, nil
+	}
+
+	// parse Ads.txt file, the charset declared in the response Content-Type header is used, unless set by the crawler parse options
+	options := append([]ParseOption{Charset(contentTypeCharset(d.res))}, c.parseOptions...)
+	records, err := ParseBody(d.body, options...)
+	if err != nil {
+		return nil, err
+	}
+	// fetch warnings are file level warnings (with no line index)
+	records.Warnings = append(records.Warnings, d.warnings...)
+
+	// Ads.txt response
+	r := &Response{
+		Request:   req,
+		Records:   records,
+		Attempts:  d.attempts,
+		Variant:   d.variant,
+		Fallbacks: d.fallbacks,
+		FinalURL:  d.finalURL(),
+		Hops:      d.hops,
+		Header:    d.res.Header,
+		BodySize:  int64(len(d.body)),
+	}
+
+	// parse Ads.txt expiration date from response caching headers (else default expiration time is used)
+	r.Expires, r.ExpiresSource = c.expires(d.res)
+
+	if c.cache != nil {
+		r.CacheStatus = CacheMiss
+		c.cache.Set(cacheKey(req), newCacheEntry(r))
+	}
+
+	return r, nil
 }
 
-// GetMultiple crawl and parse multiple Ads.txt files from remote hosts based on Ads.txt Specification Version 1.0.1
-// https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
-func GetMultiple(req []*Request, h Handler) {
+// GetMultiple crawl and parse multiple Ads.txt files from remote hosts using the crawler settings
+func (c *Crawler) GetMultiple(req []*Request, h Handler) {
+	c.GetMultipleContext(context.Background(), req, h)
+}
+
+// GetMultipleContext crawl and parse multiple Ads.txt files from remote hosts using the crawler settings. Once the provided
+// context is cancelled all running requests are aborted, and the requests that did not start yet are passed to the handler
+// with the context error
+func (c *Crawler) GetMultipleContext(ctx context.Context, req []*Request, h Handler) {
 	// For faster crawling, use new goroutine for each request and set waitgroup to wait for all goroutine to finish
 	var wg sync.WaitGroup
 	wg.Add(len(req))
@@ -84,13 +126,20 @@ func GetMultiple(req []*Request, h Handler) {
 	// buffer of channels to handle response
 	for _, r := range req {
 		// block if guard channel is already filled, to avoid "too many" parallel requests at the same time
-		guard <- struct{}{}
+		select {
+		case guard <- struct{}{}:
+		case <-ctx.Done():
+			h.Handle(r, nil, ctx.Err())
+			wg.Done()
+			continue
+		}
+
 		// crawl and parse request
 		go func(r *Request) {
-			res, err := Get(r)
+			defer wg.Done()
+			res, err := c.GetContext(ctx, r)
 			h.Handle(r, res, err)
 			<-guard
-			defer wg.Done()
 		}(r)
 	}
 
@@ -98,45 +147,127 @@ func GetMultiple(req []*Request, h Handler) {
 	wg.Wait()
 }
 
+// ParseOption configure how Ads.txt file is parsed
+type ParseOption func(*parseOptions)
+
+// parseOptions Ads.txt file parse settings
+type parseOptions struct {
+	discardBody bool   // discardBody do not keep the file lines in Records.Body
+	charset     string // charset declared for the file
+}
+
+// newParseOptions return parse settings with the options applied
+func newParseOptions(options []ParseOption) *parseOptions {
+	o := &parseOptions{}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// DiscardBody do not keep the original file content in Records.Body, to reduce the memory used for large files
+func DiscardBody() ParseOption {
+	return func(o *parseOptions) {
+		o.discardBody = true
+	}
+}
+
 // ParseBody parse Ads.txt file based on Ads.txt Specification Version 1.0.1
 // https://iabtechlab.com/wp-content/uploads/2017/09/IABOpenRTB_Ads.txt_Public_Spec_V1-0-1.pdf
-func ParseBody(b []byte) (*Records, error) {
-	// use custom split function to support different end-of-line marker (CR, CRLF etc)
-	split := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		if atEOF && len(data) == 0 {
-			return 0, nil, nil
-		}
-		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
-			if data[i] == '\n' {
-				// We have a line terminated by single newline.
-				return i + 1, data[0:i], nil
-			}
-			advance = i + 1
-			if len(data) > i+1 && data[i+1] == '\n' {
-				advance++
-			}
-			return advance, data[0:i], nil
+func ParseBody(b []byte, options ...ParseOption) (*Records, error) {
+	return ParseReader(bytes.NewReader(b), options...)
+}
+
+// ParseReader parse Ads.txt file from reader line by line, without reading the entire file into memory. After the file is
+// parsed, its data records are validated for duplicate and conflicting declarations
+func ParseReader(rd io.Reader, options ...ParseOption) (*Records, error) {
+	o := newParseOptions(options)
+
+	r := newRecords()
+	lines := []*recordLine{}
+	err := ScanRecords(rd, func(l *Line) bool {
+		if !o.discardBody && l.Index > 0 {
+			r.Body = append(r.Body, l.Text)
 		}
-		// If we're at EOF, we have a final, non-terminated line. Return it.
-		if atEOF {
-			return len(data), data, nil
+		if l.DataRecord != nil {
+			lines = append(lines, &recordLine{index: l.Index, text: l.Text, r: l.DataRecord})
 		}
-		// Request more data.
-		return 0, nil, nil
+		r.add(l)
+		return true
+	}, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(b))
-	scanner.Split(split)
+	r.Warnings = append(r.Warnings, validateRecords(lines)...)
+	return r, nil
+}
 
-	// loop over Ads.txt file lines and parse each line
-	lines := []string{}
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+// ScanRecords parse Ads.txt file from reader line by line and call fn for each line (including comments and empty lines),
+// until fn return false. Parsed records are not kept in memory (other than Variables, that are needed to validate
+// following Variables), so files of any size can be parsed with bounded memory. The file is transcoded to UTF-8 if needed,
+// and encoding warnings are reported as file level warnings, in a Line with index 0. Duplicate and conflicting data
+// records are reported only by ParseReader, that keep all the file data records
+func ScanRecords(rd io.Reader, fn func(l *Line) bool, options ...ParseOption) error {
+	o := newParseOptions(options)
+
+	rd, w := decodeReader(rd, o.charset)
+	if w != nil && !fn(&Line{Warning: w}) {
+		return nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	scanner := bufio.NewScanner(rd)
+	scanner.Split(scanLines)
+
+	// loop over Ads.txt file lines and parse each line
+	vars := newRecords()
+	invalidUTF8 := false
+	for index := 1; scanner.Scan(); index++ {
+		txt, decoded := decodeLine(scanner.Text())
+		if decoded && !invalidUTF8 {
+			invalidUTF8 = true
+			w := &Warning{Level: LowSeverity, Message: fmt.Sprintf(warnInvalidUTF8, index)}
+			if !fn(&Line{Warning: w}) {
+				return nil
+			}
+		}
+
+		l := vars.parseLine(index, txt)
+		if l.Variable != nil {
+			vars.Variables = append(vars.Variables, l.Variable)
+		}
+		if !fn(l) {
+			return nil
+		}
 	}
 
-	return parseRecords(lines), nil
+	return scanner.Err()
+}
+
+// scanLines split function that support different end-of-line marker (CR, CRLF etc)
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			// We have a line terminated by single newline.
+			return i + 1, data[0:i], nil
+		}
+		// CR at the end of the buffer may be followed by LF, request more data
+		if i == len(data)-1 && !atEOF {
+			return 0, nil, nil
+		}
+		advance = i + 1
+		if len(data) > i+1 && data[i+1] == '\n' {
+			advance++
+		}
+		return advance, data[0:i], nil
+	}
+	// If we're at EOF, we have a final, non-terminated line. Return it.
+	if atEOF {
+		return len(data), data, nil
+	}
+	// Request more data.
+	return 0, nil, nil
 }
@@ -1,11 +1,16 @@
 package adstxt
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 // TestGetMultiple testing fetch and parse multile Ads.txt files from remote hosts
@@ -74,6 +79,58 @@ func TestGet(t *testing.T) {
 	}
 }
 
+// TestGetContextCancel testing fetch Ads.txt file is aborted once the context deadline expires
+func TestGetContextCancel(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// block until the test is done, so the request can complete only by cancelling it
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	req, _ := NewRequest(ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := GetContext(ctx, req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected request to fail with deadline exceeded error and not [%v]", err)
+	}
+}
+
+// TestGetMultipleContextCancel testing requests are passed to the handler with context error once the context is cancelled
+func TestGetMultipleContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := []*Request{
+		&Request{URL: "http://example.com/ads.txt", Domain: "example.com"},
+		&Request{URL: "http://test.com/ads.txt", Domain: "test.com"},
+	}
+
+	var mu sync.Mutex
+	handled := 0
+	h := func(req *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled++
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected request [%s] to fail with context cancelled error and not [%v]", req.URL, err)
+		}
+	}
+
+	GetMultipleContext(ctx, requests, HandlerFunc(h))
+
+	if handled != len(requests) {
+		t.Errorf("Expected all [%d] requests to be handled and not [%d]", len(requests), handled)
+	}
+}
+
 // TestParseBody test paring []byte array into []Line array
 func TestParseBody(t *testing.T) {
 	body := []string{
@@ -172,3 +229,96 @@ func TestParseRecordsFailure(t *testing.T) {
 	}
 
 }
+
+// TestParseRecordsMultipleOwnerDomain test parsing Ads.txt file with multiple OWNERDOMAIN and MANAGERDOMAIN declarations
+func TestParseRecordsMultipleOwnerDomain(t *testing.T) {
+	b := []byte("OWNERDOMAIN=example.com\nOWNERDOMAIN=example.net\nMANAGERDOMAIN=manager.com\nMANAGERDOMAIN=manager.com,US\nMANAGERDOMAIN=other.com,us")
+	res, err := ParseBody(b)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(res.Variables) != 5 {
+		t.Errorf("Failed to parse Ads.txt variables, expected number of variables to be 5 and not [%d]", len(res.Variables))
+	}
+
+	if len(res.Warnings) != 2 {
+		t.Fatalf("Expected 2 warnings when parsing Ads.txt with multiple declarations but received [%d]", len(res.Warnings))
+	}
+
+	if res.Warnings[0].Index != 2 || res.Warnings[1].Index != 5 {
+		t.Errorf("Expected warnings for lines 2 and 5 but received lines [%d] and [%d]", res.Warnings[0].Index, res.Warnings[1].Index)
+	}
+}
+
+// TestParseRecordsManagerDomainExtraFields test MANAGERDOMAIN declaration with extra fields is parsed as variable and not
+// as data record
+func TestParseRecordsManagerDomainExtraFields(t *testing.T) {
+	res, err := ParseBody([]byte("MANAGERDOMAIN=a.com,US,extra\ngreenadexchange.com, XF7342, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.DataRecords) != 1 || len(res.Variables) != 0 {
+		t.Errorf("Expected [1] data record and no variables and not [%d], [%d]", len(res.DataRecords), len(res.Variables))
+	}
+
+	const expected = "managerdomain must be declared as <domain>,<country code> (optional) pattern"
+	if len(res.Warnings) != 1 || res.Warnings[0].Index != 1 || res.Warnings[0].Message != expected {
+		t.Fatalf("Expected single warning [%s] for line 1 and not [%v]", expected, res.Warnings)
+	}
+	if res.Warnings[0].Level != HighSeverity || res.Warnings[0].Fix != nil {
+		t.Errorf("Expected high severity warning with no suggested fix and not [%v]", res.Warnings[0])
+	}
+}
+
+// TestParseReader test parsing Ads.txt file from reader, reading a single byte at a time
+func TestParseReader(t *testing.T) {
+	b := "greenadexchange.com, XF7342, DIRECT\r\n#comment\r\nsubdomain=dev.example.com\rgreenadexchange.com, XF7343, RESELLER"
+
+	res, err := ParseReader(iotest.OneByteReader(strings.NewReader(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Body) != 4 || len(res.DataRecords) != 2 || len(res.Variables) != 1 || len(res.Warnings) != 0 {
+		t.Errorf("Expected [4] lines, [2] records, [1] variable and no warnings and not [%d], [%d], [%d], [%d]",
+			len(res.Body), len(res.DataRecords), len(res.Variables), len(res.Warnings))
+	}
+
+	res, err = ParseReader(strings.NewReader(b), DiscardBody())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Body) != 0 || len(res.DataRecords) != 2 {
+		t.Errorf("Expected body to be discarded and [2] records and not [%d] lines, [%d] records", len(res.Body), len(res.DataRecords))
+	}
+}
+
+// TestScanRecords test scanning Ads.txt file line by line, and stop scanning when the callback return false
+func TestScanRecords(t *testing.T) {
+	b := "OWNERDOMAIN=example.com\ngreenadexchange.com, XF7342, DIRECT\nOWNERDOMAIN=example.net\nnot a record\ngreenadexchange.com, XF7343, DIRECT"
+
+	lines := []*Line{}
+	err := ScanRecords(strings.NewReader(b), func(l *Line) bool {
+		lines = append(lines, l)
+		return l.Index < 4
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 4 {
+		t.Fatalf("Expected scanning to stop after [4] lines and not [%d]", len(lines))
+	}
+	if lines[1].DataRecord == nil || lines[1].Index != 2 {
+		t.Errorf("Expected line [2] to be data record and not [%+v]", lines[1])
+	}
+	// variables are validated against previous lines
+	if lines[2].Variable == nil || lines[2].Warning == nil {
+		t.Errorf("Expected line [3] to be variable with duplicate OWNERDOMAIN warning and not [%+v]", lines[2])
+	}
+	if lines[3].Warning == nil || lines[3].Warning.Index != 4 {
+		t.Errorf("Expected line [4] warning and not [%+v]", lines[3])
+	}
+}
@@ -742,3 +742,22 @@ func vaidateAdSystemCName(domain string) error {
 
 	return nil
 }
+
+// normalizeAdSystemDomain return the canonical domain of a known ad system, or the lower case domain name if the ad system
+// is not known or has no declared canonical domain. It allows matching different domain names of the same ad system
+func normalizeAdSystemDomain(domain string) string {
+	lcDomain := strings.ToLower(strings.TrimSpace(domain))
+
+	adSystemDomain, ok := adSystemDomains[lcDomain]
+	if !ok {
+		return lcDomain
+	}
+
+	adSystem, ok := adSystems[adSystemDomain.ID]
+	if !ok || len(adSystem.CanonicalDomain) == 0 {
+		return lcDomain
+	}
+
+	// the first canonical name is used when the ad system declared several canonical domains
+	return strings.TrimSpace(strings.Split(adSystem.CanonicalDomain, ",")[0])
+}
@@ -0,0 +1,126 @@
+package adstxt
+
+import (
+	"strings"
+	"sync"
+)
+
+// Authorization holds the relationships declared in Ads.txt file for single seller account on a publisher domain
+type Authorization struct {
+	Direct          bool   `json:"direct"`                    // Direct seller account is declared as DIRECT
+	Reseller        bool   `json:"reseller"`                  // Reseller seller account is declared as RESELLER
+	CertAuthorityID string `json:"certauthorityid,omitempty"` // CertAuthorityID of the advertising system (if declared)
+}
+
+// authKey identify single seller account of an advertising system on a publisher domain
+type authKey struct {
+	adSystem  string // adSystem normalized advertising system domain
+	accountID string // accountID publisher account ID
+}
+
+// newAuthKey return the key of seller account ID on ad system domain
+func newAuthKey(adSystemDomain string, accountID string) authKey {
+	return authKey{adSystem: normalizeAdSystemDomain(adSystemDomain), accountID: strings.TrimSpace(accountID)}
+}
+
+// Authorizer holds an in memory index of Ads.txt data records, used to check if a seller account is authorized to sell
+// inventory of a publisher domain. Authorizer is safe for concurrent use: lookups can run while new Ads.txt responses are
+// added. Use separate Authorizer for ads.txt and app-ads.txt responses
+type Authorizer struct {
+	mu      sync.RWMutex
+	domains map[string]map[authKey]*Authorization // domains data records index, keyed by publisher root domain
+}
+
+// NewAuthorizer create new empty Authorizer
+func NewAuthorizer() *Authorizer {
+	return &Authorizer{domains: map[string]map[authKey]*Authorization{}}
+}
+
+// Add index the data records of Ads.txt response, replacing any data records previously added for the same publisher domain
+func (a *Authorizer) Add(res *Response) {
+	if res == nil || res.Request == nil || res.Records == nil {
+		return
+	}
+
+	// build the domain index before taking the lock, so lookups are blocked only while the index is replaced
+	index := make(map[authKey]*Authorization, len(res.DataRecords))
+	for _, r := range res.DataRecords {
+		k := dataRecordKey(r)
+		auth, ok := index[k]
+		if !ok {
+			auth = &Authorization{}
+			index[k] = auth
+		}
+
+		switch r.AccountType {
+		case accountTypeDirect:
+			auth.Direct = true
+		case accountTypeReseller:
+			auth.Reseller = true
+		}
+		if len(auth.CertAuthorityID) == 0 {
+			auth.CertAuthorityID = r.CertAuthorityID
+		}
+	}
+
+	a.mu.Lock()
+	a.domains[strings.ToLower(res.Domain)] = index
+	a.mu.Unlock()
+}
+
+// AddMultiple index the data records of multiple Ads.txt responses
+func (a *Authorizer) AddMultiple(res []*Response) {
+	for _, r := range res {
+		a.Add(r)
+	}
+}
+
+// Remove all data records indexed for publisher root domain
+func (a *Authorizer) Remove(domain string) {
+	a.mu.Lock()
+	delete(a.domains, strings.ToLower(domain))
+	a.mu.Unlock()
+}
+
+// Len return the number of publisher domains indexed
+func (a *Authorizer) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.domains)
+}
+
+// Lookup return the relationships declared in the Ads.txt file of publisher root domain for seller account ID on ad system
+// domain. Ad system domain is normalized to its canonical domain, so any known domain name of the ad system can be used.
+// Surrounding whitespace of the ad system domain and account ID is ignored, the same as in the indexed data records
+func (a *Authorizer) Lookup(domain string, adSystemDomain string, accountID string) (Authorization, bool) {
+	k := newAuthKey(adSystemDomain, accountID)
+
+	a.mu.RLock()
+	auth, ok := a.domains[strings.ToLower(domain)][k]
+	a.mu.RUnlock()
+
+	if !ok {
+		return Authorization{}, false
+	}
+	return *auth, true
+}
+
+// Authorized return true if seller account ID on ad system domain is authorized to sell inventory of publisher root domain
+// with the specified account type (DIRECT or RESELLER, case insensitive). Empty account type matches any relationship
+func (a *Authorizer) Authorized(domain string, adSystemDomain string, accountID string, accountType string) bool {
+	auth, ok := a.Lookup(domain, adSystemDomain, accountID)
+	if !ok {
+		return false
+	}
+
+	switch strings.ToUpper(accountType) {
+	case accountTypeDirect:
+		return auth.Direct
+	case accountTypeReseller:
+		return auth.Reseller
+	case "":
+		return auth.Direct || auth.Reseller
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package adstxt
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestAuthorizer test indexing Ads.txt responses and authorization lookups
+func TestAuthorizer(t *testing.T) {
+	rec, err := ParseBody([]byte("google.com, pub-1234, DIRECT, f08c47fec0942fa0\ngreenadexchange.com, XF7342, RESELLER\ngreenadexchange.com, XF7342, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewAuthorizer()
+	a.Add(&Response{Request: &Request{Domain: "example.com"}, Records: rec})
+
+	if a.Len() != 1 {
+		t.Errorf("Expected single indexed domain and not [%d]", a.Len())
+	}
+
+	lookups := []struct {
+		domain      string
+		adSystem    string
+		accountID   string
+		accountType string
+		expected    bool
+	}{
+		{"example.com", "google.com", "pub-1234", "DIRECT", true},
+		{"example.com", "googletagservices.com", "pub-1234", "direct", true},
+		{"example.com", "google.com", "pub-1234", "RESELLER", false},
+		{"example.com", "greenadexchange.com", "XF7342", "RESELLER", true},
+		{"example.com", "GreenAdExchange.com", "XF7342", "DIRECT", true},
+		{"example.com", "greenadexchange.com", "XF7342", "", true},
+		{"example.com", "greenadexchange.com", "XF0000", "", false},
+		{"example.com", " greenadexchange.com ", " XF7342 ", "RESELLER", true},
+		{"example.com", "google.com", "pub-1234\t", "DIRECT", true},
+		{"test.com", "google.com", "pub-1234", "DIRECT", false},
+	}
+
+	for _, l := range lookups {
+		if a.Authorized(l.domain, l.adSystem, l.accountID, l.accountType) != l.expected {
+			t.Errorf("Expected authorization of [%s] [%s] [%s] [%s] to be [%t]", l.domain, l.adSystem, l.accountID, l.accountType, l.expected)
+		}
+	}
+
+	auth, ok := a.Lookup("example.com", "google.com", "pub-1234")
+	if !ok || auth.CertAuthorityID != "f08c47fec0942fa0" {
+		t.Errorf("Expected lookup to return certification authority ID [f08c47fec0942fa0] and not [%s]", auth.CertAuthorityID)
+	}
+
+	// re-adding domain replaces its previous records
+	rec, _ = ParseBody([]byte("greenadexchange.com, XF7342, RESELLER"))
+	a.Add(&Response{Request: &Request{Domain: "example.com"}, Records: rec})
+	if a.Authorized("example.com", "google.com", "pub-1234", "") {
+		t.Errorf("Expected records of replaced Ads.txt file to be removed")
+	}
+
+	a.Remove("example.com")
+	if a.Authorized("example.com", "greenadexchange.com", "XF7342", "") {
+		t.Errorf("Expected records of removed domain to be removed")
+	}
+}
+
+// TestAuthorizerConcurrency test concurrent lookups while Ads.txt responses are added
+func TestAuthorizerConcurrency(t *testing.T) {
+	rec, _ := ParseBody([]byte("greenadexchange.com, XF7342, DIRECT"))
+	a := NewAuthorizer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.Add(&Response{Request: &Request{Domain: "example.com"}, Records: rec})
+		}()
+		go func() {
+			defer wg.Done()
+			a.Authorized("example.com", "greenadexchange.com", "XF7342", "DIRECT")
+		}()
+	}
+	wg.Wait()
+
+	if !a.Authorized("example.com", "greenadexchange.com", "XF7342", "DIRECT") {
+		t.Errorf("Expected seller account to be authorized")
+	}
+}
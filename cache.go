@@ -0,0 +1,241 @@
+package adstxt
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CacheStatus how a response was served when the crawler has a cache
+type CacheStatus string
+
+// cache statuses
+const (
+	CacheMiss        CacheStatus = "miss"        // file was not cached, or was changed since it was cached
+	CacheHit         CacheStatus = "hit"         // cached file has not expired, the remote host was not called
+	CacheRevalidated CacheStatus = "revalidated" // cached file has expired, and the remote host confirmed it was not changed (304)
+)
+
+// Cache stores the last Response fetched for each Ads.txt request. Cached responses that have not expired are returned
+// without calling the remote host, and expired responses are revalidated using conditional request
+type Cache interface {
+	Get(key string) (*CacheEntry, bool) // Get return the cache entry of the key, if cached
+	Set(key string, e *CacheEntry)      // Set add or replace the cache entry of the key
+}
+
+// CacheEntry cached Response and its validators
+type CacheEntry struct {
+	Response     *Response `json:"response"`     // Response last fetched
+	ETag         string    `json:"etag"`         // ETag header of the response
+	LastModified string    `json:"lastModified"` // LastModified header of the response
+}
+
+// WithCache set the crawler Ads.txt response cache. Responses returned from the cache share their records with the cache,
+// and should not be modified
+func WithCache(cache Cache) Option {
+	return func(c *Crawler) {
+		c.cache = cache
+	}
+}
+
+// cacheKey return the cache key of the request
+func cacheKey(req *Request) string {
+	return req.URL
+}
+
+// newCacheEntry create new cache entry for response
+func newCacheEntry(r *Response) *CacheEntry {
+	return &CacheEntry{
+		Response:     r,
+		ETag:         r.Header.Get("ETag"),
+		LastModified: r.Header.Get("Last-Modified"),
+	}
+}
+
+// conditionalKey context key of the cache entry used to send conditional request
+type conditionalKey struct{}
+
+// withConditional return context that make the crawler send conditional request for the cached file final URL
+func withConditional(ctx context.Context, e *CacheEntry) context.Context {
+	if len(e.ETag) == 0 && len(e.LastModified) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, conditionalKey{}, e)
+}
+
+// conditional return the cache entry used to send conditional request to URL, or nil if the request is not conditional
+func conditional(ctx context.Context, u string) *CacheEntry {
+	e, ok := ctx.Value(conditionalKey{}).(*CacheEntry)
+	if !ok || e.Response.FinalURL != u {
+		return nil
+	}
+	return e
+}
+
+// setConditionalHeaders add If-None-Match and If-Modified-Since headers to conditional request for URL
+func setConditionalHeaders(ctx context.Context, httpRequest *http.Request, u string) {
+	e := conditional(ctx, u)
+	if e == nil {
+		return
+	}
+	if len(e.ETag) > 0 {
+		httpRequest.Header.Set("If-None-Match", e.ETag)
+	}
+	if len(e.LastModified) > 0 {
+		httpRequest.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// revalidated return copy of the cached response, refreshed with the not modified (304) response headers
+func (c *Crawler) revalidated(e *CacheEntry, d *download) *Response {
+	r := *e.Response
+
+	// headers of not modified response update the cached response headers (RFC 9111 section 4.3.4)
+	r.Header = e.Response.Header.Clone()
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+	for k, v := range d.res.Header {
+		r.Header[k] = v
+	}
+
+	r.Attempts = d.attempts
+	r.Variant = d.variant
+	r.Fallbacks = d.fallbacks
+	r.Hops = d.hops
+
+	// expiration is computed from the not modified response, with the updated cached response headers
+	res := *d.res
+	res.Header = r.Header
+	r.Expires, r.ExpiresSource = c.expires(&res)
+	r.CacheStatus = CacheRevalidated
+	return &r
+}
+
+// LRUCache in-memory Cache that keeps up to a maximum number of entries, evicting the least recently used entry. LRUCache
+// is safe for concurrent use
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries *list.List
+	items   map[string]*list.Element
+}
+
+// lruItem single LRUCache entry
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache create new in-memory cache of up to size entries
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, entries: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get return the cache entry of the key, if cached
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.entries.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+// Set add or replace the cache entry of the key
+func (c *LRUCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruItem).entry = e
+		c.entries.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruItem{key: key, entry: e})
+	for c.size > 0 && c.entries.Len() > c.size {
+		el := c.entries.Back()
+		c.entries.Remove(el)
+		delete(c.items, el.Value.(*lruItem).key)
+	}
+}
+
+// Len return the number of cached entries
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// DiskCache Cache that stores each entry as JSON file in a directory. DiskCache is safe for concurrent use
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache create new on-disk cache in dir, creating the directory if it does not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path return the file path of the key cache entry
+func (c *DiskCache) path(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(h[:])+".json")
+}
+
+// Get return the cache entry of the key, if cached
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	e := &CacheEntry{}
+	if err := json.Unmarshal(b, e); err != nil || e.Response == nil {
+		log.Printf("[%s] failed to read cache entry [%v]", key, err)
+		return nil, false
+	}
+	return e, true
+}
+
+// Set add or replace the cache entry of the key. The entry is written to temporary file first, so concurrent readers
+// never see partially written entry
+func (c *DiskCache) Set(key string, e *CacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[%s] failed to write cache entry [%s]", key, err.Error())
+		return
+	}
+
+	f, err := ioutil.TempFile(c.dir, ".entry-*")
+	if err != nil {
+		log.Printf("[%s] failed to write cache entry [%s]", key, err.Error())
+		return
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		log.Printf("[%s] failed to write cache entry [%s]", key, err.Error())
+	}
+}
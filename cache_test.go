@@ -0,0 +1,151 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLRUCache test LRU cache evict the least recently used entry
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", &CacheEntry{ETag: "a"})
+	c.Set("b", &CacheEntry{ETag: "b"})
+
+	// use "a" so "b" is the least recently used entry
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Expected [a] to be cached")
+	}
+	c.Set("c", &CacheEntry{ETag: "c"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Expected [b] to be evicted")
+	}
+	if e, ok := c.Get("a"); !ok || e.ETag != "a" {
+		t.Errorf("Expected [a] to be cached and not [%v]", e)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected [2] cached entries and not [%d]", c.Len())
+	}
+}
+
+// TestDiskCache test disk cache store and load cache entries
+func TestDiskCache(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get("http://example.com/ads.txt"); ok {
+		t.Errorf("Expected empty cache")
+	}
+
+	req, _ := NewRequest("example.com")
+	rec, _ := ParseBody([]byte("greenadexchange.com, XF7342, DIRECT"))
+	c.Set(req.URL, &CacheEntry{Response: &Response{Request: req, Records: rec, FinalURL: req.URL}, ETag: `"v1"`})
+
+	e, ok := c.Get(req.URL)
+	if !ok {
+		t.Fatalf("Expected [%s] to be cached", req.URL)
+	}
+	if e.ETag != `"v1"` || e.Response.Domain != "example.com" || len(e.Response.DataRecords) != 1 {
+		t.Errorf("Expected cached entry to be loaded and not [%+v]", e)
+	}
+}
+
+// TestGetCache test crawler return cached file that has not expired, and send conditional request for expired file
+func TestGetCache(t *testing.T) {
+	var requests, notModified int32
+	maxAge := "max-age=0"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", maxAge)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com, XF7342, DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithCache(NewLRUCache(10)))
+	req, _ := NewRequest(ts.URL)
+
+	// first request is fetched from remote host
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CacheStatus != CacheMiss || len(res.DataRecords) != 1 {
+		t.Errorf("Expected cache miss with [1] record and not [%s] with [%d]", res.CacheStatus, len(res.DataRecords))
+	}
+
+	// cached file expired immediately, so it is revalidated, and now expires in one hour
+	maxAge = "max-age=3600"
+	res, err = c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CacheStatus != CacheRevalidated || len(res.DataRecords) != 1 || res.ExpiresSource != ExpiresMaxAge {
+		t.Errorf("Expected revalidated response with [1] record and not [%s] with [%d]", res.CacheStatus, len(res.DataRecords))
+	}
+	if atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("Expected conditional request to be sent")
+	}
+
+	// cached file has not expired, remote host is not called
+	res, err = c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CacheStatus != CacheHit || len(res.DataRecords) != 1 {
+		t.Errorf("Expected cache hit with [1] record and not [%s] with [%d]", res.CacheStatus, len(res.DataRecords))
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Expected [2] requests to remote host and not [%d]", n)
+	}
+}
+
+// TestGetCacheInvalidExpires test revalidating cached file when the not modified response has invalid Expires header
+func TestGetCacheInvalidExpires(t *testing.T) {
+	var notModified int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.Header().Set("Expires", "0")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		// file expires immediately, so the next request is revalidated
+		w.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com, XF7342, DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithCache(NewLRUCache(10)))
+	req, _ := NewRequest(ts.URL)
+
+	if _, err := c.Get(req); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.CacheStatus != CacheRevalidated || len(res.DataRecords) != 1 || atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("Expected revalidated response with [1] record and not [%s] with [%d]", res.CacheStatus, len(res.DataRecords))
+	}
+	if res.ExpiresSource != ExpiresHeader || res.Expires.After(time.Now()) {
+		t.Errorf("Expected revalidated file with invalid Expires header to expire immediately and not [%s] [%s]", res.ExpiresSource, res.Expires)
+	}
+}
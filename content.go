@@ -0,0 +1,79 @@
+package adstxt
+
+import (
+	"bytes"
+	"strings"
+)
+
+// content errors\warnings
+const (
+	errSoft404          = "[%s] %s URL [%s] returned %s instead of %s file"
+	warnBadContentType  = "%s file content type should be ‘%s’ and not [%s]"
+	contentSniffMaxSize = 4096 // contentSniffMaxSize number of bytes from the beginning of the body inspected for soft 404
+)
+
+// soft 404 page phrases, for text that has no valid Ads.txt record
+var (
+	parkedDomainPhrases = []string{"domain is for sale", "buy this domain", "domain may be for sale", "domain parking", "parked domain", "parked free"}
+	notFoundPhrases     = []string{"404 not found", "page not found", "file not found", "error 404", "not found"}
+)
+
+// WithLenientContentType make the crawler accept files served with the wrong content type (i.e. ‘application/octet-stream’
+// or no content type at all) when the content looks like the requested file, and add a warning to the response. By
+// default such files are rejected with ErrBadContentType error
+func WithLenientContentType() Option {
+	return func(c *Crawler) {
+		c.lenientContentType = true
+	}
+}
+
+// detectSoft404 return description of the body if it is HTML page, parked domain page or not found page served instead
+// of the requested file (with success status code), or empty string otherwise
+func detectSoft404(req *Request, body []byte) string {
+	head := body
+	if len(head) > contentSniffMaxSize {
+		head = head[:contentSniffMaxSize]
+	}
+	text := strings.ToLower(string(bytes.TrimLeft(head, " \t\r\n\xef\xbb\xbf")))
+
+	// neither Ads.txt nor sellers.json file can start with HTML tag
+	if strings.HasPrefix(text, "<") {
+		for _, tag := range []string{"<!doctype html", "<html", "<head", "<body", "<script", "<meta", "<div", "<title"} {
+			if strings.Contains(text, tag) {
+				return "HTML page"
+			}
+		}
+	}
+
+	// error pages served as plain text are detected only if the file has no valid record
+	if req.FileType == SellersJSON || looksLikeFile(req, body) {
+		return ""
+	}
+	for _, p := range parkedDomainPhrases {
+		if strings.Contains(text, p) {
+			return "parked domain page"
+		}
+	}
+	for _, p := range notFoundPhrases {
+		if strings.Contains(text, p) {
+			return "not found page"
+		}
+	}
+
+	return ""
+}
+
+// looksLikeFile return true if the body looks like the requested file: JSON object for sellers.json file, or at least one
+// valid Data\Variable record for Ads.txt file
+func looksLikeFile(req *Request, body []byte) bool {
+	if req.FileType == SellersJSON {
+		return bytes.HasPrefix(bytes.TrimLeft(body, " \t\r\n\xef\xbb\xbf"), []byte("{"))
+	}
+
+	found := false
+	ScanRecords(bytes.NewReader(body), func(l *Line) bool {
+		found = l.Warning == nil && (l.DataRecord != nil || l.Variable != nil)
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,83 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDetectSoft404 test detecting HTML, parked domain and not found pages served instead of Ads.txt file
+func TestDetectSoft404(t *testing.T) {
+	req, _ := NewRequest("example.com")
+
+	tests := []struct {
+		body string
+		page string
+	}{
+		{"greenadexchange.com, XF7342, DIRECT", ""},
+		{"# empty file", ""},
+		{"", ""},
+		{"\n  <!DOCTYPE html>\n<html><body>Welcome</body></html>", "HTML page"},
+		{"<html><head><title>404</title></head></html>", "HTML page"},
+		{"This domain is for sale! Contact us", "parked domain page"},
+		{"404 Not Found", "not found page"},
+		// Ads.txt file with valid records is never a not found page
+		{"# page not found errors are ignored\ngreenadexchange.com, XF7342, DIRECT", ""},
+	}
+
+	for _, test := range tests {
+		if page := detectSoft404(req, []byte(test.body)); page != test.page {
+			t.Errorf("Expected body [%s] to be detected as [%s] and not [%s]", test.body, test.page, page)
+		}
+	}
+}
+
+// TestGetSoft404 test crawler return ErrSoft404 error for HTML page served as Ads.txt file
+func TestGetSoft404(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "<!doctype html><html><body>Page not found</body></html>")
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	_, err := Get(req)
+	if !errors.Is(err, ErrSoft404) {
+		t.Errorf("Expected error [%s] and not [%v]", ErrSoft404, err)
+	}
+}
+
+// TestLenientContentType test crawler accept Ads.txt file served with wrong content type in lenient mode
+func TestLenientContentType(t *testing.T) {
+	body := "greenadexchange.com, XF7342, DIRECT"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	if _, err := Get(req); !errors.Is(err, ErrBadContentType) {
+		t.Errorf("Expected error [%s] and not [%v]", ErrBadContentType, err)
+	}
+
+	c := NewCrawler(WithLenientContentType())
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected [1] data record and not [%d]", len(res.DataRecords))
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Level != LowSeverity {
+		t.Errorf("Expected single low severity content type warning and not %v", res.Warnings)
+	}
+
+	// content that does not look like Ads.txt file is still rejected
+	body = "just some binary data"
+	if _, err := c.Get(req); !errors.Is(err, ErrBadContentType) {
+		t.Errorf("Expected error [%s] and not [%v]", ErrBadContentType, err)
+	}
+}
@@ -1,10 +1,16 @@
 package adstxt
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -13,7 +19,8 @@ import (
 const (
 	errHTTPClientError    = "[%s] remote host [%s] Ads.txt URL [%s]"
 	errHTTPGeneralError   = "[%s] remote host [%s] Ads.txt URL [%s]"
-	errHTTPBadContentType = "[%s] Ads.txt file content type should be ‘text/plain’ and not [%s]"
+	errHTTPBadContentType = "[%s] %s file content type should be ‘%s’ and not [%s]"
+	errNetworkError       = "[%s] failed to fetch Ads.txt URL [%s] error [%s]"
 )
 
 // parsing error\warning: each error includes Ads.txt remote host (domain level) and explanaiton about the error
@@ -21,68 +28,320 @@ const (
 	errFailToParseRedirect       = "[%s] failed to parse root domain from HTTP redirect response header. Ads.txt URL [%s] redirect [%s] error [%s]"
 	errRedirectToInvalidAdsTxt   = "[%s] failed to get Ads.txt file, redirect from [%s] to invalid Ads.txt URL [%s]"
 	errRedirectToDifferentDomain = "Only single redirect out of original root domain scope [%s] is allowed. Additional redirect from [%s] to [%s] is forbidden"
+	errTooManyRedirects          = "[%s] failed to get Ads.txt file, stopped after [%d] redirects. Ads.txt URL [%s]"
+	warnBodyTruncated            = "%s file is larger than [%d] bytes, only the first [%d] bytes were parsed"
 )
 
 // HTTP crawler settings
 const (
-	userAgent      = "+https://github.com/tzafrirben/go-adstxt-crawler"
+	userAgent      = "go-adstxt-crawler (+https://github.com/tzafrirben/go-adstxt-crawler)"
 	requestTimeout = 30
+	maxRedirects   = 10
 )
 
-// crawler provide methods for downloading Ads.txt files from remote host
-type crawler struct {
-	client    *http.Client // HTTP client used to make HTTP request for Ads.txt file from remote host
-	UserAgent string       // crawler UserAgent string
+// Crawler provide methods for downloading Ads.txt files from remote host. Crawler is safe for concurrent use, and should be
+// created once and reused for crawling many Ads.txt files
+type Crawler struct {
+	client             *http.Client      // HTTP client used to make HTTP request for Ads.txt file from remote host
+	transport          *http.Transport   // default HTTP transport, used unless custom round tripper is set
+	roundTripper       http.RoundTripper // custom HTTP round tripper
+	UserAgent          string            // crawler UserAgent string
+	maxRedirects       int               // maximum number of redirects to follow for single request
+	retryPolicy        *RetryPolicy      // retry policy for failed requests (nil for no retries)
+	robots             *robotsCache      // robots.txt cache (nil if robots.txt is ignored)
+	fallback           *Fallback         // fallback strategy for URL variants (nil for fetching the request URL only)
+	redirectPolicy     RedirectPolicy    // redirectPolicy decide which redirects are followed
+	maxBodySize        int64             // maxBodySize maximum number of bytes read from response body (0 for no limit)
+	parseOptions       []ParseOption     // parseOptions used to parse fetched Ads.txt files
+	lenientContentType bool              // lenientContentType accept files served with wrong content type that look like the requested file
+	minExpires         time.Duration     // minExpires minimum duration until a fetched file expires (0 for no limit)
+	maxExpires         time.Duration     // maxExpires maximum duration until a fetched file expires (0 for no limit)
+	cache              Cache             // cache of Ads.txt responses (nil for no caching)
 }
 
+// Option configure a Crawler
+type Option func(*Crawler)
+
+// WithUserAgent set the crawler User-Agent request header
+func WithUserAgent(ua string) Option {
+	return func(c *Crawler) {
+		c.UserAgent = ua
+	}
+}
+
+// WithTimeout set the time limit for a single HTTP request (including reading the response body). Default is 30 seconds
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Crawler) {
+		c.client.Timeout = timeout
+	}
+}
+
+// WithRoundTripper set custom HTTP round tripper used to send HTTP requests. Proxy and TLS options are applied only to
+// the default transport, and are ignored when custom round tripper is used
+func WithRoundTripper(rt http.RoundTripper) Option {
+	return func(c *Crawler) {
+		c.roundTripper = rt
+	}
+}
+
+// WithProxy send all HTTP requests through the specified proxy URL
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Crawler) {
+		c.transport.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithMaxRedirects set the maximum number of redirects followed for single request. Default is 10
+func WithMaxRedirects(n int) Option {
+	return func(c *Crawler) {
+		c.maxRedirects = n
+	}
+}
+
+// WithTLSConfig set the TLS configuration used by the default transport
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Crawler) {
+		c.transport.TLSClientConfig = cfg
+	}
+}
+
+// WithMaxBodySize set the maximum number of bytes read from the response body. Larger files are truncated at the last
+// complete line before the limit, and a warning is added to the response. By default the entire body is read
+func WithMaxBodySize(n int64) Option {
+	return func(c *Crawler) {
+		c.maxBodySize = n
+	}
+}
+
+// WithParseOptions set the options used to parse fetched Ads.txt files (i.e. DiscardBody)
+func WithParseOptions(options ...ParseOption) Option {
+	return func(c *Crawler) {
+		c.parseOptions = options
+	}
+}
+
+// defaultCrawler is used by the package level functions (Get, GetMultiple etc)
+var defaultCrawler = NewCrawler()
+
 // NewCrawler Create new crawler to fetch Ads.txt file from remote host
-func newCrawler() *crawler {
-	return &crawler{
+func NewCrawler(options ...Option) *Crawler {
+	c := &Crawler{
 		// Create client with required custom parameters.
 		// Options: Disable keep-alives, 30sec n/w call timeout, do not follow redirects by default
 		client: &http.Client{
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
-			Transport: &http.Transport{
-				DisableKeepAlives: true,
-			},
 			Timeout: time.Second * requestTimeout,
 		},
-		UserAgent: userAgent,
+		transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+		UserAgent:      userAgent,
+		maxRedirects:   maxRedirects,
+		redirectPolicy: IABRedirectPolicy(),
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	c.client.Transport = c.transport
+	if c.roundTripper != nil {
+		c.client.Transport = c.roundTripper
 	}
+
+	return c
 }
 
-// send HTTP request to fetch Ads.txt file from remote host
-func (c *crawler) sendRequest(req *Request) (*http.Response, error) {
-	httpRequest, err := http.NewRequest("GET", req.URL, nil)
+// send HTTP request to fetch Ads.txt file from remote host. The request is sent to the hop URL, and the hop is updated with
+// the response status code and the request timing
+func (c *Crawler) sendRequest(ctx context.Context, req *Request, hop *Hop) (*http.Response, error) {
+	t := &Timing{}
+	httpRequest, err := http.NewRequestWithContext(t.trace(ctx), "GET", hop.URL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	httpRequest.Header.Add("User-Agent", c.UserAgent)
-	httpRequest.Header.Add("Accept", "text/plain")
+	httpRequest.Header.Add("Accept", req.contentType())
 	httpRequest.Header.Add("Accept-Charset", "utf-8")
-	httpRequest.Header.Add("Content-Type", "text/plain; charset=utf-8")
+	httpRequest.Header.Add("Content-Type", req.contentType()+"; charset=utf-8")
+	setConditionalHeaders(ctx, httpRequest, hop.URL)
 
 	res, err := c.client.Do(httpRequest)
+	t.done()
+	hop.Timing = t
 	if err != nil {
 		return nil, err
 	}
 
+	hop.StatusCode = res.StatusCode
 	return res, nil
 }
 
-// handle HTTP redirect response: parse new redirect destination from HTTP response header
-func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, error) {
-	redirect := res.Header.Get("Location")
+// download holds the content of a file fetched from remote host, and metadata about fetching it
+type download struct {
+	res         *http.Response     // res the final HTTP response (the body is already read and closed)
+	body        []byte             // body of the fetched file
+	attempts    int                // attempts number of HTTP attempts made (including retries)
+	hops        []*Hop             // hops HTTP requests sent to fetch the file, including redirects
+	warnings    []*Warning         // warnings about fetching the file (redirects that were followed, truncated body, content type)
+	variant     string             // variant URL that was fetched successfully
+	fallbacks   []*FallbackAttempt // fallbacks outcome of each URL variant tried
+	notModified bool               // notModified the remote host response to conditional request indicates the file was not modified
+}
+
+// download fetch file from remote host and read its content. If fallback strategy is set, each of the URL variants of the
+// request is tried until the file is fetched successfully. If all variants fail, the error of the first variant that got
+// HTTP response is returned, else the last variant error. The request itself is not modified
+func (c *Crawler) download(ctx context.Context, req *Request) (*download, error) {
+	variants := []string{req.URL}
+	if c.fallback != nil {
+		variants = c.fallback.variants(req)
+	}
+
+	var lastErr error
+	reached := false
+	fallbacks := []*FallbackAttempt{}
+	for _, v := range variants {
+		d, err := c.downloadURL(ctx, req, v)
+		if err != nil {
+			fallbacks = append(fallbacks, newFallbackAttempt(v, err))
+			// cancelled request is not retried with other variants
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			// the first error of a variant host that responded (i.e. not found) is returned rather than transport errors
+			// of later variants (i.e. the "www." host does not exist)
+			if !reached {
+				var e *Error
+				reached = errors.As(err, &e) && e.StatusCode != 0
+				lastErr = err
+			}
+			continue
+		}
 
-	log.Printf("[%s]: redirect from [%s] to [%s]", res.Status, req.URL, redirect)
+		fallbacks = append(fallbacks, &FallbackAttempt{URL: v, StatusCode: d.res.StatusCode})
+		d.variant = v
+		d.fallbacks = fallbacks
+		return d, nil
+	}
+
+	return nil, lastErr
+}
+
+// finalURL return the URL the file was fetched from, after following redirects
+func (d *download) finalURL() string {
+	return d.hops[len(d.hops)-1].URL
+}
+
+// downloadURL fetch file from URL (following redirects) and read its content
+func (c *Crawler) downloadURL(ctx context.Context, req *Request, u string) (*download, error) {
+	d := &download{attempts: 1, hops: []*Hop{}, warnings: []*Warning{}}
+	res, err := c.fetch(ctx, req, u, d)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// not modified response has no body, the cached file is used
+	if res.StatusCode == http.StatusNotModified {
+		d.res = res
+		d.notModified = true
+		return d, nil
+	}
+
+	body, warnings, err := c.readBody(req, d.finalURL(), res)
+	if err != nil {
+		return nil, err
+	}
+	d.warnings = append(d.warnings, warnings...)
+
+	d.res = res
+	d.body = body
+	return d, nil
+}
+
+// fetch send HTTP request to remote host and follow redirects until the server response indicates Success (HTTP Status Code 200).
+// The HTTP requests sent (hops), number of attempts made (more than one if failed requests were retried) and redirect
+// warnings are recorded in the download. The caller is responsible to close the returned response body
+func (c *Crawler) fetch(ctx context.Context, req *Request, u string, d *download) (*http.Response, error) {
+	for redirects := 0; ; redirects++ {
+		// stop following redirects once the context is cancelled
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// make sure the remote host robots.txt allow fetching the file
+		if err := c.checkRobotsTxt(ctx, req, u); err != nil {
+			return nil, err
+		}
+
+		// count retries of each request on top of the first attempt
+		hop := &Hop{URL: u}
+		d.hops = append(d.hops, hop)
+		res, n, err := c.sendRequestWithRetry(ctx, req, hop)
+		d.attempts += n - 1
+		if err != nil {
+			// cancelled requests return the context error as is
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			return nil, networkError(req, u, err)
+		}
+
+		// handle Ads.txt response
+		switch {
+		// the server response to conditional request indicates the cached file was not modified
+		case res.StatusCode == http.StatusNotModified && conditional(ctx, u) != nil:
+			return res, nil
+		// the server response indicates redirect (301, 302, 307 status codes), follow redirect and read Ads.txt
+		// file from the source of the redirect
+		case 300 <= res.StatusCode && res.StatusCode < 400:
+			res.Body.Close()
+			if redirects >= c.maxRedirects {
+				return nil, newError(ErrRedirect, req, u, res, nil, errTooManyRedirects, req.Domain, redirects, u)
+			}
+			redirect, w, err := c.handleRedirect(req, d.hops, res)
+			if err != nil {
+				return nil, err
+			}
+			if w != nil {
+				d.warnings = append(d.warnings, w)
+			}
+			u = redirect
+		// the server response indicates Success (HTTP Status Code 200)
+		case res.StatusCode == 200:
+			return res, nil
+		// client error, server error or un known HTTP status in remote server response
+		default:
+			res.Body.Close()
+			return nil, statusError(req, u, res)
+		}
+	}
+}
+
+// handle HTTP redirect response of the last hop: parse new redirect destination from HTTP response header, record it in the
+// hop and check it is allowed by the crawler redirect policy
+func (c *Crawler) handleRedirect(req *Request, hops []*Hop, res *http.Response) (string, *Warning, error) {
+	hop := hops[len(hops)-1]
+	hop.Location = res.Header.Get("Location")
+
+	// resolve relative redirect location against the hop URL
+	redirect := hop.Location
+	if base, err := url.Parse(hop.URL); err == nil {
+		if loc, err := base.Parse(hop.Location); err == nil {
+			redirect = loc.String()
+		}
+	}
+
+	log.Printf("[%s]: redirect from [%s] to [%s]", res.Status, hop.URL, redirect)
 
 	// Check if redirect destination has the same root domain as the request initial root doamin.
 	d, err := rootDomain(redirect)
 	if err != nil {
-		return "", fmt.Errorf(errFailToParseRedirect, req.Domain, req.URL, redirect, err.Error())
+		return "", nil, newError(ErrRedirect, req, hop.URL, res, err, errFailToParseRedirect, req.Domain, hop.URL, redirect, err.Error())
 	}
 
 	// According to IAB ads.txt specification, section 3.1 "ACCESS METHOD":
@@ -90,45 +349,73 @@ func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, erro
 	// the advertising system should follow the redirect and consume the data as authoritative for the source of the redirect,
 	// if and only if the redirect is within scope of the original root domain as defined above.
 	// Multiple redirects are valid as long as each redirect location remains within the original root domain."
-	if d != req.Domain {
-		// If redirect to different domain, check that this is the first redirect to different domain
-		// According to IAB ads.txt specification, section 3.1 "ACCESS METHOD":
-		// "Only a single HTTP redirect to a destination outside the original root domain is allowed to
-		// facilitate one-hop delegation of authority to a third party's web server domain."
-		prevDomain, _ := rootDomain(req.URL)
-		if prevDomain != req.Domain && prevDomain != d {
-			return "", fmt.Errorf(errRedirectToDifferentDomain, req.Domain, prevDomain, d)
+	hop.OutOfDomain = d != req.Domain
+
+	// redirect back to URL that was already requested would loop until max redirects is reached
+	for _, h := range hops {
+		if h.URL == redirect {
+			return "", nil, newError(ErrRedirect, req, hop.URL, res, nil, errRedirectLoop, req.Domain, hop.URL, redirect)
 		}
 	}
 
-	// make sure redirects takes us to another Ads.txt file and not just to home page
-	if !strings.HasSuffix(redirect, "/ads.txt") {
-		return "", fmt.Errorf(errRedirectToInvalidAdsTxt, req.Domain, req.URL, redirect)
+	w, err := c.redirectPolicy.CheckRedirect(req, hops, redirect)
+	if err != nil {
+		return "", nil, newError(ErrRedirect, req, hop.URL, res, err, "%s", err.Error())
 	}
 
-	return redirect, nil
+	return redirect, w, nil
 }
 
-// Read HTTP response body
-func (c *crawler) readBody(req *Request, res *http.Response) ([]byte, error) {
-	// The HTTP Content-type should be ‘text/plain’, and all other Content-types should be treated as
-	// an error and the content ignored
+// Read HTTP response body of the file fetched from URL. It return warnings if the body was truncated or served with the
+// wrong content type (in lenient content type mode)
+func (c *Crawler) readBody(req *Request, u string, res *http.Response) ([]byte, []*Warning, error) {
+	// The HTTP Content-type should be ‘text/plain’ (‘application/json’ for sellers.json), and all other Content-types
+	// should be treated as an error and the content ignored (unless lenient content type mode is set)
 	contentType := res.Header.Get("Content-Type")
-	if strings.Index(contentType, "text/plain") != 0 {
-		return nil, fmt.Errorf(errHTTPBadContentType, req.URL, contentType)
+	badContentType := strings.Index(contentType, req.contentType()) != 0
+	if badContentType && !c.lenientContentType {
+		return nil, nil, newError(ErrBadContentType, req, u, res, nil, errHTTPBadContentType, u, req.fileName(), req.contentType(), contentType)
 	}
 
-	// read response body
-	body, err := ioutil.ReadAll(res.Body)
+	// read response body, up to the max body size
+	var r io.Reader = res.Body
+	if c.maxBodySize > 0 {
+		r = io.LimitReader(res.Body, c.maxBodySize+1)
+	}
+	body, err := ioutil.ReadAll(r)
 	if err != nil {
-		return nil, err
+		return nil, nil, networkError(req, u, err)
+	}
+
+	warnings := []*Warning{}
+
+	// drop the partial last line of truncated body
+	if c.maxBodySize > 0 && int64(len(body)) > c.maxBodySize {
+		body = body[:c.maxBodySize]
+		if i := bytes.LastIndexAny(body, "\r\n"); i >= 0 {
+			body = body[:i+1]
+		}
+		log.Printf("[%s] %s file [%s] is larger than [%d] bytes and was truncated", req.Domain, req.fileName(), u, c.maxBodySize)
+		warnings = append(warnings, &Warning{Level: HighSeverity, Message: fmt.Sprintf(warnBodyTruncated, req.fileName(), c.maxBodySize, len(body))})
+	}
+
+	// HTML page, parked domain or error page served with success status code
+	if page := detectSoft404(req, body); len(page) > 0 {
+		return nil, nil, newError(ErrSoft404, req, u, res, nil, errSoft404, req.Domain, req.fileName(), u, page, req.fileName())
+	}
+
+	if badContentType {
+		if !looksLikeFile(req, body) {
+			return nil, nil, newError(ErrBadContentType, req, u, res, nil, errHTTPBadContentType, u, req.fileName(), req.contentType(), contentType)
+		}
+		warnings = append(warnings, &Warning{Level: LowSeverity, Message: fmt.Sprintf(warnBadContentType, req.fileName(), req.contentType(), contentType)})
 	}
 
-	return body, nil
+	return body, warnings, nil
 }
 
 // parse Ads.txt file expiration date from the response Expires header
-func (c *crawler) parseExpires(res *http.Response) (time.Time, error) {
+func (c *Crawler) parseExpires(res *http.Response) (time.Time, error) {
 	expires := res.Header.Get("Expires")
 	if len(expires) == 0 {
 		return time.Time{}, fmt.Errorf("Failed to parse expires from response header")
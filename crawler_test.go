@@ -1,6 +1,9 @@
 package adstxt
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -23,15 +26,15 @@ func TestSendAndParseRquest(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req, &Hop{URL: req.URL})
 	if err != nil {
 		t.Error(err)
 	}
 
 	defer res.Body.Close()
 
-	body, err := c.readBody(req, res)
+	body, _, err := c.readBody(req, req.URL, res)
 	if err != nil {
 		t.Error(err)
 	}
@@ -55,8 +58,8 @@ func TestHandleRedirect(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req, &Hop{URL: req.URL})
 	if err != nil {
 		t.Error(err)
 	}
@@ -64,7 +67,7 @@ func TestHandleRedirect(t *testing.T) {
 	defer res.Body.Close()
 
 	// parse redirect location
-	r, err := c.handleRedirect(req, res)
+	r, _, err := c.handleRedirect(req, []*Hop{{URL: req.URL}}, res)
 	if err != nil {
 		t.Error(err)
 	}
@@ -74,6 +77,47 @@ func TestHandleRedirect(t *testing.T) {
 	}
 }
 
+// TestHandleAppAdsTxtRedirect test crawler handle HTTP redirect response for app-ads.txt request
+func TestHandleAppAdsTxtRedirect(t *testing.T) {
+	redirect := "http://gotest.com/app-ads.txt"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", redirect)
+		w.WriteHeader(http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	// request mock
+	req, _ := NewAppRequest(ts.URL)
+
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req, &Hop{URL: req.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	r, _, err := c.handleRedirect(req, []*Hop{{URL: req.URL}}, res)
+	if err != nil {
+		t.Error(err)
+	}
+	if r != redirect {
+		t.Errorf("Expected redirect destination to be [%s] and not [%s]", redirect, r)
+	}
+
+	// redirect from app-ads.txt to Ads.txt file is not allowed
+	redirect = "http://gotest.com/ads.txt"
+	res, err = c.sendRequest(context.Background(), req, &Hop{URL: req.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if _, _, err := c.handleRedirect(req, []*Hop{{URL: req.URL}}, res); err == nil {
+		t.Errorf("Expected redirect from [%s] to [%s] to fail", req.URL, redirect)
+	}
+}
+
 // TestParseExpires test parse Ads.txt file expires from HTTP response Header
 func TestParseExpires(t *testing.T) {
 	// expected response
@@ -89,8 +133,8 @@ func TestParseExpires(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req, &Hop{URL: req.URL})
 	if err != nil {
 		t.Error(err)
 	}
@@ -107,3 +151,157 @@ func TestParseExpires(t *testing.T) {
 	}
 
 }
+
+// TestCrawlerOptions test crawler settings are applied to Ads.txt requests
+func TestCrawlerOptions(t *testing.T) {
+	const ua = "test-crawler/1.0"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != ua {
+			t.Errorf("Expected request User-Agent to be [%s] and not [%s]", ua, r.Header.Get("User-Agent"))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	// count requests sent using custom round tripper
+	var requests int
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	c := NewCrawler(WithUserAgent(ua), WithTimeout(5*time.Second), WithRoundTripper(rt))
+
+	req, _ := NewRequest(ts.URL)
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected single DataRecord but found [%d]", len(res.DataRecords))
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected request to be sent using custom round tripper")
+	}
+}
+
+// TestCrawlerMaxRedirects test crawler stops following redirects after max redirects limit
+func TestCrawlerMaxRedirects(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// each redirect is to a new URL, so only the max redirects limit stops the crawler
+		w.Header().Set("Location", ts.URL+"/r"+r.URL.Path)
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithMaxRedirects(3))
+
+	req, _ := NewRequest(ts.URL)
+	_, err := c.Get(req)
+
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrRedirect {
+		t.Fatalf("Expected redirects to fail with [%s] and not [%v]", ErrRedirect, err)
+	}
+	if e.URL != ts.URL+"/r/r/r/ads.txt" {
+		t.Errorf("Expected redirects to stop at [%s] and not [%s]", ts.URL+"/r/r/r/ads.txt", e.URL)
+	}
+}
+
+// roundTripperFunc adapter to use function as http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// TestRedirectChain test the crawler record each redirect followed, the final URL and response metadata without modifying
+// the request
+func TestRedirectChain(t *testing.T) {
+	const body = "greenadexchange.com,XF7342,DIRECT"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ads.txt":
+			w.Header().Set("Location", "/moved/ads.txt")
+			w.WriteHeader(http.StatusMovedPermanently)
+		case "/moved/ads.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("X-Test", "final")
+			io.WriteString(w, body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	initial := req.URL
+
+	res, err := NewCrawler().Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.URL != initial {
+		t.Errorf("Expected request URL [%s] not to change and not [%s]", initial, req.URL)
+	}
+	if res.FinalURL != ts.URL+"/moved/ads.txt" {
+		t.Errorf("Expected final URL to be [%s] and not [%s]", ts.URL+"/moved/ads.txt", res.FinalURL)
+	}
+	if len(res.Hops) != 2 {
+		t.Fatalf("Expected [2] hops and not [%d]", len(res.Hops))
+	}
+
+	first := res.Hops[0]
+	if first.URL != initial || first.StatusCode != http.StatusMovedPermanently || first.Location != "/moved/ads.txt" || first.OutOfDomain {
+		t.Errorf("Expected first hop to be redirect from [%s] and not [%+v]", initial, first)
+	}
+	if res.Hops[1].StatusCode != http.StatusOK || res.Hops[1].Timing == nil || res.Hops[1].Timing.Total <= 0 {
+		t.Errorf("Expected final hop to have status [200] and timing and not [%+v]", res.Hops[1])
+	}
+	if res.Header.Get("X-Test") != "final" {
+		t.Errorf("Expected final response header [X-Test] to be [final] and not [%s]", res.Header.Get("X-Test"))
+	}
+	if res.BodySize != int64(len(body)) {
+		t.Errorf("Expected body size [%d] and not [%d]", len(body), res.BodySize)
+	}
+}
+
+// TestCrawlerMaxBodySize test the crawler truncate large files at the last complete line and add a warning
+func TestCrawlerMaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(w, "greenadexchange.com, XF%04d, DIRECT\n", i)
+		}
+	}))
+	defer ts.Close()
+
+	// each line is 36 bytes, so 3 complete lines fit in 120 bytes
+	c := NewCrawler(WithMaxBodySize(120), WithParseOptions(DiscardBody()))
+
+	req, _ := NewRequest(ts.URL)
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.DataRecords) != 3 {
+		t.Errorf("Expected [3] data records and not [%d]", len(res.DataRecords))
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Index != 0 {
+		t.Errorf("Expected single truncated body warning and not [%v]", res.Warnings)
+	}
+	if res.BodySize != 108 {
+		t.Errorf("Expected body size to be [108] and not [%d]", res.BodySize)
+	}
+	if len(res.Body) != 0 {
+		t.Errorf("Expected body to be discarded and not [%d] lines", len(res.Body))
+	}
+}
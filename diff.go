@@ -0,0 +1,198 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecordsDiff changes between two Ads.txt files. Data records are matched by their normalized ad system domain and
+// publisher account ID, and variables by their type and country code, regardless of the order of the lines in the files
+type RecordsDiff struct {
+	AddedDataRecords   []*DataRecord       `json:"addedDataRecords"`   // AddedDataRecords data records found only in the new file
+	RemovedDataRecords []*DataRecord       `json:"removedDataRecords"` // RemovedDataRecords data records found only in the old file
+	ChangedDataRecords []*DataRecordChange `json:"changedDataRecords"` // ChangedDataRecords data records with changed account type or cert authority ID
+	AddedVariables     []*Variable         `json:"addedVariables"`     // AddedVariables variables found only in the new file
+	RemovedVariables   []*Variable         `json:"removedVariables"`   // RemovedVariables variables found only in the old file
+	ChangedVariables   []*VariableChange   `json:"changedVariables"`   // ChangedVariables variables with changed value
+}
+
+// DataRecordChange data record of the same ad system and publisher account ID in the old and new file
+type DataRecordChange struct {
+	Old *DataRecord `json:"old"` // Old data record
+	New *DataRecord `json:"new"` // New data record
+}
+
+// VariableChange variable of the same type (and country code) in the old and new file
+type VariableChange struct {
+	Old *Variable `json:"old"` // Old variable
+	New *Variable `json:"new"` // New variable
+}
+
+// variableKey identify variable declaration in Ads.txt file
+type variableKey struct {
+	varType     string
+	countryCode string
+}
+
+// Diff return the data records and variables added, removed or changed from the old file to the new file. Nil Records
+// are handled as empty file
+func Diff(old, new *Records) *RecordsDiff {
+	if old == nil {
+		old = newRecords()
+	}
+	if new == nil {
+		new = newRecords()
+	}
+
+	d := &RecordsDiff{
+		AddedDataRecords:   []*DataRecord{},
+		RemovedDataRecords: []*DataRecord{},
+		ChangedDataRecords: []*DataRecordChange{},
+		AddedVariables:     []*Variable{},
+		RemovedVariables:   []*Variable{},
+		ChangedVariables:   []*VariableChange{},
+	}
+	d.diffDataRecords(old.DataRecords, new.DataRecords)
+	d.diffVariables(old.Variables, new.Variables)
+	return d
+}
+
+// diffDataRecords compare data records of the same ad system and publisher account ID: identical records are matched
+// first, and the remaining old and new records of the same key are reported as changed
+func (d *RecordsDiff) diffDataRecords(old []*DataRecord, new []*DataRecord) {
+	oldByKey := map[authKey][]*DataRecord{}
+	for _, r := range old {
+		k := dataRecordKey(r)
+		oldByKey[k] = append(oldByKey[k], r)
+	}
+
+	unmatched := []*DataRecord{}
+	for _, r := range new {
+		k := dataRecordKey(r)
+		if i := indexDataRecord(oldByKey[k], r); i != -1 {
+			oldByKey[k] = append(oldByKey[k][:i], oldByKey[k][i+1:]...)
+			continue
+		}
+		unmatched = append(unmatched, r)
+	}
+
+	for _, r := range unmatched {
+		k := dataRecordKey(r)
+		if len(oldByKey[k]) == 0 {
+			d.AddedDataRecords = append(d.AddedDataRecords, r)
+			continue
+		}
+		d.ChangedDataRecords = append(d.ChangedDataRecords, &DataRecordChange{Old: oldByKey[k][0], New: r})
+		oldByKey[k] = oldByKey[k][1:]
+	}
+
+	// removed records are reported in the old file order
+	for _, r := range old {
+		k := dataRecordKey(r)
+		if len(oldByKey[k]) > 0 && oldByKey[k][0] == r {
+			d.RemovedDataRecords = append(d.RemovedDataRecords, r)
+			oldByKey[k] = oldByKey[k][1:]
+		}
+	}
+}
+
+// diffVariables compare variables of the same type and country code: identical variables are matched first, and the
+// remaining old and new variables of the same key are reported as changed
+func (d *RecordsDiff) diffVariables(old []*Variable, new []*Variable) {
+	oldByKey := map[variableKey][]*Variable{}
+	for _, v := range old {
+		k := variableKey{varType: v.Type, countryCode: strings.ToUpper(v.CountryCode)}
+		oldByKey[k] = append(oldByKey[k], v)
+	}
+
+	unmatched := []*Variable{}
+	for _, v := range new {
+		k := variableKey{varType: v.Type, countryCode: strings.ToUpper(v.CountryCode)}
+		if i := indexVariable(oldByKey[k], v); i != -1 {
+			oldByKey[k] = append(oldByKey[k][:i], oldByKey[k][i+1:]...)
+			continue
+		}
+		unmatched = append(unmatched, v)
+	}
+
+	for _, v := range unmatched {
+		k := variableKey{varType: v.Type, countryCode: strings.ToUpper(v.CountryCode)}
+		if len(oldByKey[k]) == 0 {
+			d.AddedVariables = append(d.AddedVariables, v)
+			continue
+		}
+		d.ChangedVariables = append(d.ChangedVariables, &VariableChange{Old: oldByKey[k][0], New: v})
+		oldByKey[k] = oldByKey[k][1:]
+	}
+
+	for _, v := range old {
+		k := variableKey{varType: v.Type, countryCode: strings.ToUpper(v.CountryCode)}
+		if len(oldByKey[k]) > 0 && oldByKey[k][0] == v {
+			d.RemovedVariables = append(d.RemovedVariables, v)
+			oldByKey[k] = oldByKey[k][1:]
+		}
+	}
+}
+
+// dataRecordKey return the key of the data record seller account
+func dataRecordKey(r *DataRecord) authKey {
+	return newAuthKey(r.AdverterDomain, r.PublisherAccountID)
+}
+
+// indexDataRecord return the index of data record with the same account type and cert authority ID as r, or -1
+func indexDataRecord(records []*DataRecord, r *DataRecord) int {
+	for i, o := range records {
+		if strings.EqualFold(o.AccountType, r.AccountType) && strings.EqualFold(o.CertAuthorityID, r.CertAuthorityID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexVariable return the index of variable with the same value as v (case insensitive), or -1
+func indexVariable(variables []*Variable, v *Variable) int {
+	for i, o := range variables {
+		if strings.EqualFold(o.Value, v.Value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Empty return true if the files have the same data records and variables
+func (d *RecordsDiff) Empty() bool {
+	return len(d.AddedDataRecords) == 0 && len(d.RemovedDataRecords) == 0 && len(d.ChangedDataRecords) == 0 &&
+		len(d.AddedVariables) == 0 && len(d.RemovedVariables) == 0 && len(d.ChangedVariables) == 0
+}
+
+// custom "toString" method: human readable diff, one change per line. Added lines are prefixed with "+", removed lines
+// with "-" and changed lines with "~"
+func (d *RecordsDiff) String() string {
+	str := []string{}
+	for _, r := range d.AddedDataRecords {
+		str = append(str, "+ "+formatDataRecord(r))
+	}
+	for _, r := range d.RemovedDataRecords {
+		str = append(str, "- "+formatDataRecord(r))
+	}
+	for _, c := range d.ChangedDataRecords {
+		changes := []string{}
+		if !strings.EqualFold(c.Old.AccountType, c.New.AccountType) {
+			changes = append(changes, fmt.Sprintf("account type %s -> %s", c.Old.AccountType, c.New.AccountType))
+		}
+		if !strings.EqualFold(c.Old.CertAuthorityID, c.New.CertAuthorityID) {
+			changes = append(changes, fmt.Sprintf("cert authority ID [%s] -> [%s]", c.Old.CertAuthorityID, c.New.CertAuthorityID))
+		}
+		str = append(str, fmt.Sprintf("~ %s, %s: %s", c.New.AdverterDomain, c.New.PublisherAccountID, strings.Join(changes, ", ")))
+	}
+	for _, v := range d.AddedVariables {
+		str = append(str, "+ "+formatVariable(v))
+	}
+	for _, v := range d.RemovedVariables {
+		str = append(str, "- "+formatVariable(v))
+	}
+	for _, c := range d.ChangedVariables {
+		str = append(str, fmt.Sprintf("~ %s: %s -> %s", strings.ToUpper(c.New.Type), c.Old.Value, c.New.Value))
+	}
+	return strings.Join(str, "\n")
+}
@@ -0,0 +1,85 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestDiff test diff of data records and variables between two Ads.txt files
+func TestDiff(t *testing.T) {
+	old, err := ParseBody([]byte(strings.Join([]string{
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0",
+		"greenadexchange.com, XF7342, DIRECT",
+		"appnexus.com, 1234, RESELLER",
+		"CONTACT=adops@example.com",
+		"OWNERDOMAIN=example.com",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := ParseBody([]byte(strings.Join([]string{
+		"OWNERDOMAIN=example.net",
+		"appnexus.com, 5678, DIRECT",
+		// line order and ad system domain case do not matter
+		"GreenAdExchange.com, XF7342, RESELLER",
+		"google.com, pub-1234, direct, f08c47fec0942fa0",
+		"CONTACT=adops@example.com",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := Diff(old, new)
+	if len(d.AddedDataRecords) != 1 || d.AddedDataRecords[0].PublisherAccountID != "5678" {
+		t.Errorf("Expected appnexus.com 5678 data record to be added and not [%v]", d.AddedDataRecords)
+	}
+	if len(d.RemovedDataRecords) != 1 || d.RemovedDataRecords[0].PublisherAccountID != "1234" {
+		t.Errorf("Expected appnexus.com 1234 data record to be removed and not [%v]", d.RemovedDataRecords)
+	}
+	if len(d.ChangedDataRecords) != 1 || d.ChangedDataRecords[0].Old.AccountType != "DIRECT" || d.ChangedDataRecords[0].New.AccountType != "RESELLER" {
+		t.Errorf("Expected greenadexchange.com XF7342 data record to change from DIRECT to RESELLER and not [%v]", d.ChangedDataRecords)
+	}
+	if len(d.AddedVariables) != 0 || len(d.RemovedVariables) != 0 || len(d.ChangedVariables) != 1 || d.ChangedVariables[0].New.Value != "example.net" {
+		t.Errorf("Expected OWNERDOMAIN variable to change and not [%v] [%v] [%v]", d.AddedVariables, d.RemovedVariables, d.ChangedVariables)
+	}
+
+	expected := strings.Join([]string{
+		"+ appnexus.com, 5678, DIRECT",
+		"- appnexus.com, 1234, RESELLER",
+		"~ GreenAdExchange.com, XF7342: account type DIRECT -> RESELLER",
+		"~ OWNERDOMAIN: example.com -> example.net",
+	}, "\n")
+	if d.String() != expected {
+		t.Errorf("Expected diff [%s] and not [%s]", expected, d.String())
+	}
+
+	j, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(j), `"changedDataRecords":[{"old":{"adverterdomain":"greenadexchange.com"`) {
+		t.Errorf("Expected JSON encoded diff and not [%s]", j)
+	}
+}
+
+// TestDiffCertAuthorityID test changed cert authority ID and empty diff
+func TestDiffCertAuthorityID(t *testing.T) {
+	old, _ := ParseBody([]byte("google.com, pub-1234, DIRECT, f08c47fec0942fa0"))
+	new, _ := ParseBody([]byte("google.com, pub-1234, DIRECT"))
+
+	d := Diff(old, new)
+	if len(d.ChangedDataRecords) != 1 || d.ChangedDataRecords[0].New.CertAuthorityID != "" {
+		t.Errorf("Expected cert authority ID change and not [%v]", d.ChangedDataRecords)
+	}
+	if d.String() != "~ google.com, pub-1234: cert authority ID [f08c47fec0942fa0] -> []" {
+		t.Errorf("Expected cert authority ID change and not [%s]", d.String())
+	}
+
+	if d := Diff(old, old); !d.Empty() {
+		t.Errorf("Expected empty diff and not [%s]", d.String())
+	}
+	if d := Diff(nil, old); len(d.AddedDataRecords) != 1 {
+		t.Errorf("Expected nil records to be handled as empty file")
+	}
+}
@@ -0,0 +1,144 @@
+package adstxt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// file level validation warnings
+const (
+	warnDuplicate            = "Duplicate data record, declared in lines %s"
+	warnCaseDuplicate        = "Duplicate data record (case insensitive), declared in lines %s"
+	warnRelationshipConflict = "Seller account %s on %s is declared both as %s and %s, in lines %s"
+	warnCertAuthorityID      = "Inconsistent Certification Authority IDs %s declared for %s, in lines %s"
+)
+
+// recordLine data record and the line it was parsed from
+type recordLine struct {
+	index int
+	text  string
+	r     *DataRecord
+}
+
+// validateRecords check the data records of Ads.txt file for exact duplicates, case insensitive duplicates, accounts
+// declared both DIRECT and RESELLER and ad systems declared with different cert authority IDs. Each warning reference all
+// the lines involved, and is reported on the first line after the first declaration
+func validateRecords(lines []*recordLine) []*Warning {
+	warnings := []*Warning{}
+
+	exact := groupRecordLines(lines, func(r *DataRecord) string {
+		return strings.Join([]string{r.AdverterDomain, r.PublisherAccountID, r.AccountType, r.CertAuthorityID}, ",")
+	})
+	for _, g := range exact {
+		if len(g) > 1 {
+			warnings = append(warnings, newLinesWarning(g, LowSeverity, warnDuplicate))
+		}
+	}
+
+	// case insensitive duplicates are reported only if the lines differ by more than case
+	caseInsensitive := groupRecordLines(lines, func(r *DataRecord) string {
+		return strings.ToLower(strings.Join([]string{r.AdverterDomain, r.PublisherAccountID, r.AccountType, r.CertAuthorityID}, ","))
+	})
+	for _, g := range caseInsensitive {
+		if len(g) > 1 && !sameRecords(g) {
+			warnings = append(warnings, newLinesWarning(g, LowSeverity, warnCaseDuplicate))
+		}
+	}
+
+	accounts := groupRecordLines(lines, func(r *DataRecord) string {
+		k := dataRecordKey(r)
+		return k.adSystem + "," + k.accountID
+	})
+	for _, g := range accounts {
+		types := distinct(g, func(r *DataRecord) string { return strings.ToUpper(r.AccountType) })
+		if len(types) > 1 {
+			k := dataRecordKey(g[0].r)
+			warnings = append(warnings, newLinesWarning(g, LowSeverity, warnRelationshipConflict, k.accountID, k.adSystem,
+				accountTypeDirect, accountTypeReseller))
+		}
+	}
+
+	certIDs := groupRecordLines(lines, func(r *DataRecord) string {
+		if len(r.CertAuthorityID) == 0 {
+			return ""
+		}
+		return normalizeAdSystemDomain(r.AdverterDomain)
+	})
+	for _, g := range certIDs {
+		ids := distinct(g, func(r *DataRecord) string { return strings.ToLower(r.CertAuthorityID) })
+		if len(ids) > 1 {
+			warnings = append(warnings, newLinesWarning(g, HighSeverity, warnCertAuthorityID, strings.Join(ids, ", "),
+				normalizeAdSystemDomain(g[0].r.AdverterDomain)))
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Lines[0] < warnings[j].Lines[0]
+	})
+	return warnings
+}
+
+// groupRecordLines group the lines by key, in the order of their first line. Lines with empty key are ignored
+func groupRecordLines(lines []*recordLine, key func(r *DataRecord) string) [][]*recordLine {
+	groups := [][]*recordLine{}
+	index := map[string]int{}
+	for _, l := range lines {
+		k := key(l.r)
+		if len(k) == 0 {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, []*recordLine{})
+		}
+		groups[i] = append(groups[i], l)
+	}
+	return groups
+}
+
+// distinct return the distinct values of the lines data records, in order
+func distinct(lines []*recordLine, value func(r *DataRecord) string) []string {
+	values := []string{}
+	seen := map[string]bool{}
+	for _, l := range lines {
+		v := value(l.r)
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sameRecords return true if all the lines data records are identical
+func sameRecords(lines []*recordLine) bool {
+	for _, l := range lines[1:] {
+		if *l.r != *lines[0].r {
+			return false
+		}
+	}
+	return true
+}
+
+// newLinesWarning return warning that reference all the lines, reported on the second line. The lines numbers are
+// added as the last format argument
+func newLinesWarning(lines []*recordLine, level Severity, format string, a ...interface{}) *Warning {
+	indexes := make([]int, len(lines))
+	numbers := make([]string, len(lines))
+	for i, l := range lines {
+		indexes[i] = l.index
+		numbers[i] = fmt.Sprintf("%d", l.index)
+	}
+
+	a = append(a, strings.Join(numbers, ", "))
+	return &Warning{
+		Index:   lines[1].index,
+		Text:    lines[1].text,
+		Message: fmt.Sprintf(format, a...),
+		Level:   level,
+		Lines:   indexes,
+	}
+}
@@ -0,0 +1,54 @@
+package adstxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestValidateRecords test duplicate and conflicting data records warnings
+func TestValidateRecords(t *testing.T) {
+	body := strings.Join([]string{
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0",
+		"appnexus.com, 1234, RESELLER",
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0",
+		"Google.com, pub-1234, DIRECT, F08C47FEC0942FA0",
+		"greenadexchange.com, XF7342, DIRECT",
+		"greenadexchange.com, XF7342, RESELLER",
+		"google.com, pub-5678, RESELLER, 0000000000000000",
+		"appnexus.com, 5678, RESELLER",
+	}, "\n")
+
+	rec, err := ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		index int
+		lines []int
+		level Severity
+	}{
+		// exact duplicate
+		{3, []int{1, 3}, LowSeverity},
+		// case insensitive duplicate
+		{3, []int{1, 3, 4}, LowSeverity},
+		// cert authority ID differ
+		{3, []int{1, 3, 4, 7}, HighSeverity},
+		// DIRECT and RESELLER
+		{6, []int{5, 6}, LowSeverity},
+	}
+
+	if len(rec.Warnings) != len(expected) {
+		t.Fatalf("Expected [%d] warnings and not [%d] %s", len(expected), len(rec.Warnings), rec)
+	}
+	for i, e := range expected {
+		w := rec.Warnings[i]
+		if w.Index != e.index || !reflect.DeepEqual(w.Lines, e.lines) || w.Level != e.level {
+			t.Errorf("Expected warning on line [%d] involving lines %v and not [%d] %v [%s]", e.index, e.lines, w.Index, w.Lines, w.Message)
+		}
+	}
+	if rec.Warnings[3].Message != "Seller account XF7342 on greenadexchange.com is declared both as DIRECT and RESELLER, in lines 5, 6" {
+		t.Errorf("Expected relationship conflict warning and not [%s]", rec.Warnings[3].Message)
+	}
+}
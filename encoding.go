@@ -0,0 +1,91 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// encoding warnings: Ads.txt file should be encoded in UTF-8 (section 3.2 "FILE FORMAT" of IAB Ads.txt specification)
+const (
+	warnUTF8BOM        = "file starts with UTF-8 byte order mark (BOM), which should be removed"
+	warnUTF16          = "file is encoded in UTF-16 and was transcoded, file should be encoded in UTF-8"
+	warnCharset        = "file is encoded in [%s] and was transcoded, file should be encoded in UTF-8"
+	warnUnknownCharset = "file declared charset [%s] is not supported, file is parsed as UTF-8"
+	warnInvalidUTF8    = "file is not valid UTF-8 (first invalid line [%d]), invalid lines were decoded as windows-1252"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Charset set the declared charset of the file (i.e. from HTTP Content-Type header). File byte order mark (BOM), if any,
+// take precedence over the declared charset
+func Charset(charset string) ParseOption {
+	return func(o *parseOptions) {
+		o.charset = charset
+	}
+}
+
+// decodeReader return reader that transcode the file to UTF-8 based on its byte order mark (BOM) or declared charset, and
+// a warning describing the file encoding issue, if any
+func decodeReader(rd io.Reader, charset string) (io.Reader, *Warning) {
+	br := bufio.NewReader(rd)
+	bom, _ := br.Peek(3)
+
+	switch {
+	case bytes.HasPrefix(bom, utf8BOM):
+		br.Discard(len(utf8BOM))
+		return br, &Warning{Level: LowSeverity, Message: warnUTF8BOM}
+	case bytes.HasPrefix(bom, []byte{0xFF, 0xFE}):
+		d := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+		return transform.NewReader(br, d), &Warning{Level: LowSeverity, Message: warnUTF16}
+	case bytes.HasPrefix(bom, []byte{0xFE, 0xFF}):
+		d := unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()
+		return transform.NewReader(br, d), &Warning{Level: LowSeverity, Message: warnUTF16}
+	}
+
+	charset = strings.ToLower(strings.Trim(strings.TrimSpace(charset), `"`))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return br, nil
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return br, &Warning{Level: LowSeverity, Message: fmt.Sprintf(warnUnknownCharset, charset)}
+	}
+	if name, _ := htmlindex.Name(enc); name == "utf-8" {
+		return br, nil
+	}
+
+	return transform.NewReader(br, enc.NewDecoder()), &Warning{Level: LowSeverity, Message: fmt.Sprintf(warnCharset, charset)}
+}
+
+// decodeLine decode line that is not valid UTF-8 as windows-1252, the most common encoding of such files
+func decodeLine(txt string) (string, bool) {
+	if utf8.ValidString(txt) {
+		return txt, false
+	}
+	if s, err := charmap.Windows1252.NewDecoder().String(txt); err == nil {
+		return s, true
+	}
+	return strings.ToValidUTF8(txt, "�"), true
+}
+
+// contentTypeCharset return the charset parameter of HTTP response Content-Type header
+func contentTypeCharset(res *http.Response) string {
+	_, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
@@ -0,0 +1,71 @@
+package adstxt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseEncoding test parsing Ads.txt file with BOM or encoded in charset other than UTF-8
+func TestParseEncoding(t *testing.T) {
+	tests := []struct {
+		body    []byte
+		charset string
+		warning string
+	}{
+		// UTF-8 with BOM
+		{append([]byte{0xEF, 0xBB, 0xBF}, "greenadexchange.com, XF7342, DIRECT"...), "", warnUTF8BOM},
+		// UTF-16 little endian with BOM
+		{[]byte{0xFF, 0xFE, 'g', 0, 'o', 0, 'o', 0, 'g', 0, 'l', 0, 'e', 0, '.', 0, 'c', 0, 'o', 0, 'm', 0, ',', 0, '1', 0, ',', 0, 'D', 0, 'I', 0, 'R', 0, 'E', 0, 'C', 0, 'T', 0}, "", warnUTF16},
+		// UTF-16 big endian with BOM
+		{[]byte{0xFE, 0xFF, 0, 'g', 0, 'o', 0, 'o', 0, 'g', 0, 'l', 0, 'e', 0, '.', 0, 'c', 0, 'o', 0, 'm', 0, ',', 0, '1', 0, ',', 0, 'D', 0, 'I', 0, 'R', 0, 'E', 0, 'C', 0, 'T'}, "", warnUTF16},
+		// declared charset
+		{[]byte("# caf\xe9\ngreenadexchange.com, XF7342, DIRECT"), "ISO-8859-1", "file is encoded in [iso-8859-1] and was transcoded, file should be encoded in UTF-8"},
+		// invalid UTF-8 without declared charset
+		{[]byte("greenadexchange.com, XF7342, DIRECT\n# caf\xe9"), "", "file is not valid UTF-8 (first invalid line [2]), invalid lines were decoded as windows-1252"},
+		// unknown charset
+		{[]byte("greenadexchange.com, XF7342, DIRECT"), "x-unknown", "file declared charset [x-unknown] is not supported, file is parsed as UTF-8"},
+	}
+
+	for _, test := range tests {
+		res, err := ParseBody(test.body, Charset(test.charset))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.DataRecords) != 1 {
+			t.Errorf("Expected [1] data record and not [%d] (warnings %v)", len(res.DataRecords), res.Warnings)
+		}
+		if len(res.Warnings) != 1 || res.Warnings[0].Message != test.warning || res.Warnings[0].Level != LowSeverity || res.Warnings[0].Index != 0 {
+			t.Errorf("Expected single low severity warning [%s] and not %v", test.warning, res.Warnings)
+		}
+	}
+
+	// decoded characters are kept in file body
+	res, _ := ParseBody([]byte("# caf\xe9"), Charset("windows-1252"))
+	if res.Body[0] != "# café" {
+		t.Errorf("Expected line to be decoded to [# café] and not [%s]", res.Body[0])
+	}
+}
+
+// TestGetCharset test crawler use the charset declared in the response Content-Type header
+func TestGetCharset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=windows-1252")
+		w.Write([]byte("# caf\xe9\ngreenadexchange.com, XF7342, DIRECT"))
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+	res, err := Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Body[0] != "# café" {
+		t.Errorf("Expected line to be decoded to [# café] and not [%s]", res.Body[0])
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Level != LowSeverity {
+		t.Errorf("Expected single low severity encoding warning and not %v", res.Warnings)
+	}
+}
@@ -0,0 +1,121 @@
+package adstxt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Errors returned when fetching Ads.txt file from remote host. Each error is returned wrapped in *Error, that holds the
+// request, URL and HTTP status code of the failed request, and can be tested using errors.Is
+var (
+	// ErrNotFound remote host has no Ads.txt file (HTTP status 404 or 410)
+	ErrNotFound = errors.New("file not found")
+	// ErrClientError remote host response indicates client error (HTTP status 4xx, including ErrNotFound)
+	ErrClientError = errors.New("client error")
+	// ErrServerError remote host response indicates server error (HTTP status 5xx)
+	ErrServerError = errors.New("server error")
+	// ErrUnexpectedStatus remote host response status is not success, redirect, client or server error
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	// ErrBadContentType response content type does not match the requested file type
+	ErrBadContentType = errors.New("bad content type")
+	// ErrSoft404 remote host returned HTML page, parked domain page or not found page with success status code instead of the file
+	ErrSoft404 = errors.New("soft 404")
+	// ErrRedirect redirect is not allowed by Ads.txt specification (or too many redirects)
+	ErrRedirect = errors.New("redirect violation")
+	// ErrNetwork failed to send request to remote host or read its response (including ErrDNS and ErrTLS)
+	ErrNetwork = errors.New("network error")
+	// ErrDNS failed to resolve remote host name
+	ErrDNS = errors.New("dns error")
+	// ErrTLS failed TLS handshake or certificate verification
+	ErrTLS = errors.New("tls error")
+)
+
+// Error is returned when fetching Ads.txt file from remote host fails
+type Error struct {
+	Kind       error    // Kind of the error, one of the Err* errors
+	Request    *Request // Request the failed request
+	URL        string   // URL that failed (may be different than the request initial URL after redirects)
+	StatusCode int      // StatusCode of the remote host response, or 0 if no response was received
+	Err        error    // Err underlying error (i.e. network error), if any
+	msg        string
+}
+
+// newError create new Error of the specified kind for the request that failed fetching URL
+func newError(kind error, req *Request, u string, res *http.Response, err error, format string, a ...interface{}) *Error {
+	e := &Error{
+		Kind:    kind,
+		Request: req,
+		URL:     u,
+		Err:     err,
+		msg:     fmt.Sprintf(format, a...),
+	}
+	if res != nil {
+		e.StatusCode = res.StatusCode
+	}
+	return e
+}
+
+// Error is the error interface implementation for Error type
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Unwrap return the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is return true if the target is the error kind. Not found errors are also client errors, and DNS and TLS errors are
+// also network errors
+func (e *Error) Is(target error) bool {
+	switch target {
+	case e.Kind:
+		return true
+	case ErrClientError:
+		return e.Kind == ErrNotFound
+	case ErrNetwork:
+		return e.Kind == ErrDNS || e.Kind == ErrTLS
+	}
+	return false
+}
+
+// statusError return new Error for HTTP response with status code that is not success or redirect
+func statusError(req *Request, u string, res *http.Response) *Error {
+	switch {
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone:
+		return newError(ErrNotFound, req, u, res, nil, errHTTPClientError, res.Status, req.Domain, u)
+	case 400 <= res.StatusCode && res.StatusCode < 500:
+		return newError(ErrClientError, req, u, res, nil, errHTTPClientError, res.Status, req.Domain, u)
+	case 500 <= res.StatusCode && res.StatusCode < 600:
+		return newError(ErrServerError, req, u, res, nil, errHTTPGeneralError, res.Status, req.Domain, u)
+	default:
+		return newError(ErrUnexpectedStatus, req, u, res, nil, errHTTPGeneralError, res.Status, req.Domain, u)
+	}
+}
+
+// networkError return new Error for failure to send request to remote host
+func networkError(req *Request, u string, err error) *Error {
+	return newError(networkErrorKind(err), req, u, nil, err, errNetworkError, req.Domain, u, err.Error())
+}
+
+// networkErrorKind return the kind of failure to send request to remote host: ErrDNS, ErrTLS or ErrNetwork
+func networkErrorKind(err error) error {
+	var dnsErr *net.DNSError
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrDNS
+	case errors.As(err, &recordErr), errors.As(err, &certErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ErrTLS
+	}
+	return ErrNetwork
+}
@@ -0,0 +1,88 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestErrors test errors returned when fetching Ads.txt file can be inspected using errors.Is and errors.As
+func TestErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/notfound/ads.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case "/forbidden/ads.txt":
+			w.WriteHeader(http.StatusForbidden)
+		case "/error/ads.txt":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/html/ads.txt":
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "<html></html>")
+		case "/redirect/ads.txt":
+			w.Header().Set("Location", "http://example.com/")
+			w.WriteHeader(http.StatusMovedPermanently)
+		}
+	}))
+	defer ts.Close()
+
+	errs := map[string]struct {
+		kind       error
+		statusCode int
+	}{
+		"/notfound":  {ErrNotFound, http.StatusNotFound},
+		"/forbidden": {ErrClientError, http.StatusForbidden},
+		"/error":     {ErrServerError, http.StatusInternalServerError},
+		"/html":      {ErrBadContentType, http.StatusOK},
+		"/redirect":  {ErrRedirect, http.StatusMovedPermanently},
+	}
+
+	for path, expected := range errs {
+		req, _ := NewRequest(ts.URL + path)
+		_, err := Get(req)
+
+		if !errors.Is(err, expected.kind) {
+			t.Errorf("Expected error for [%s] to be [%v] and not [%v]", path, expected.kind, err)
+		}
+
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("Expected error for [%s] to be *Error", path)
+			continue
+		}
+		if e.Request != req || e.URL != ts.URL+path+"/ads.txt" || e.StatusCode != expected.statusCode {
+			t.Errorf("Expected error for [%s] to include request, URL and status code [%d] and not [%v] [%s] [%d]",
+				path, expected.statusCode, e.Request, e.URL, e.StatusCode)
+		}
+	}
+
+	// not found error is also a client error
+	req, _ := NewRequest(ts.URL + "/notfound")
+	if _, err := Get(req); !errors.Is(err, ErrClientError) {
+		t.Errorf("Expected not found error to be client error [%v]", err)
+	}
+}
+
+// TestNetworkError test network errors are returned as ErrNetwork
+func TestNetworkError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	req, _ := NewRequest(url)
+	_, err := Get(req)
+
+	if !errors.Is(err, ErrNetwork) {
+		t.Errorf("Expected error to be network error and not [%v]", err)
+	}
+	if errors.Is(err, ErrDNS) || errors.Is(err, ErrTLS) {
+		t.Errorf("Expected error not to be DNS or TLS error [%v]", err)
+	}
+
+	// DNS and TLS errors are network errors
+	if !(&Error{Kind: ErrDNS}).Is(ErrNetwork) || !(&Error{Kind: ErrTLS}).Is(ErrNetwork) {
+		t.Errorf("Expected DNS and TLS errors to be network errors")
+	}
+}
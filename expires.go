@@ -0,0 +1,123 @@
+package adstxt
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpiresSource the source of a file expiration date
+type ExpiresSource string
+
+// file expiration date sources, in order of precedence
+const (
+	ExpiresNoCache ExpiresSource = "no-cache" // Cache-Control no-cache or no-store directive, the file expires immediately
+	ExpiresSMaxAge ExpiresSource = "s-maxage" // Cache-Control s-maxage directive
+	ExpiresMaxAge  ExpiresSource = "max-age"  // Cache-Control max-age directive
+	ExpiresHeader  ExpiresSource = "expires"  // Expires header, invalid Expires header expires immediately
+	ExpiresDefault ExpiresSource = "default"  // no caching headers, default expiration of 7 days is used
+)
+
+// Ads.txt file default expiration is set to 7 days (secion 3.6 EXPIRATION of IAB Ads.txt specification)
+const defaultExpires = 7 * 24 * time.Hour
+
+// WithExpiresLimits set minimum and maximum duration until a fetched file expires, applied to the expiration computed from
+// the response caching headers (0 for no limit)
+func WithExpiresLimits(min time.Duration, max time.Duration) Option {
+	return func(c *Crawler) {
+		c.minExpires = min
+		c.maxExpires = max
+	}
+}
+
+// expires compute file expiration date from response caching headers: Cache-Control no-cache (or no-store) take precedence
+// over s-maxage, s-maxage over max-age and max-age over Expires header (RFC 9111 section 4.2.1). The response Age is
+// subtracted from the freshness lifetime, and the result is clamped to the crawler expiration limits
+func (c *Crawler) expires(res *http.Response) (time.Time, ExpiresSource) {
+	now := time.Now().UTC()
+	cc := parseCacheControl(res.Header.Values("Cache-Control"))
+
+	var lifetime time.Duration
+	source := ExpiresDefault
+	if _, ok := cc["no-cache"]; ok {
+		source = ExpiresNoCache
+	} else if _, ok := cc["no-store"]; ok {
+		source = ExpiresNoCache
+	} else if d, ok := cacheControlSeconds(cc, "s-maxage"); ok {
+		lifetime, source = d, ExpiresSMaxAge
+	} else if d, ok := cacheControlSeconds(cc, "max-age"); ok {
+		lifetime, source = d, ExpiresMaxAge
+	} else if len(res.Header.Get("Expires")) > 0 {
+		// invalid Expires header (i.e. "0") means the file has already expired (RFC 9111 section 5.3)
+		source = ExpiresHeader
+		if expires, err := c.parseExpires(res); err == nil {
+			// Expires is relative to the response Date, to avoid clock skew between the crawler and the remote host
+			date, err := http.ParseTime(res.Header.Get("Date"))
+			if err != nil {
+				date = now
+			}
+			lifetime = expires.Sub(date)
+		}
+	}
+
+	switch source {
+	case ExpiresDefault:
+		lifetime = defaultExpires
+	case ExpiresSMaxAge, ExpiresMaxAge, ExpiresHeader:
+		// the response may have been cached by a proxy for Age seconds
+		if age, err := strconv.Atoi(strings.TrimSpace(res.Header.Get("Age"))); err == nil && age > 0 {
+			lifetime -= time.Duration(age) * time.Second
+		}
+	}
+
+	if lifetime < 0 {
+		lifetime = 0
+	}
+	if c.minExpires > 0 && lifetime < c.minExpires {
+		lifetime = c.minExpires
+	}
+	if c.maxExpires > 0 && lifetime > c.maxExpires {
+		lifetime = c.maxExpires
+	}
+
+	return now.Add(lifetime), source
+}
+
+// parseCacheControl parse Cache-Control header directives into map of lower case directive name to its value
+func parseCacheControl(values []string) map[string]string {
+	cc := map[string]string{}
+	for _, v := range values {
+		for _, directive := range strings.Split(v, ",") {
+			directive = strings.TrimSpace(directive)
+			if len(directive) == 0 {
+				continue
+			}
+
+			name, value := directive, ""
+			if i := strings.Index(directive, "="); i != -1 {
+				name, value = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+			name = strings.ToLower(strings.TrimSpace(name))
+
+			// the first occurrence of a directive is used
+			if _, ok := cc[name]; !ok {
+				cc[name] = value
+			}
+		}
+	}
+	return cc
+}
+
+// cacheControlSeconds return the duration of Cache-Control directive with delta-seconds value
+func cacheControlSeconds(cc map[string]string, name string) (time.Duration, bool) {
+	v, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,55 @@
+package adstxt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestExpires test computing file expiration date from response caching headers
+func TestExpires(t *testing.T) {
+	now := time.Now().UTC()
+	date := now.Format(http.TimeFormat)
+
+	tests := []struct {
+		header   http.Header
+		min, max time.Duration
+		expected time.Duration
+		source   ExpiresSource
+	}{
+		{http.Header{}, 0, 0, defaultExpires, ExpiresDefault},
+		{http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, 0, 0, time.Hour, ExpiresHeader},
+		// Expires is relative to the response Date
+		{http.Header{"Date": {now.Add(-time.Hour).Format(http.TimeFormat)}, "Expires": {now.Format(http.TimeFormat)}}, 0, 0, time.Hour, ExpiresHeader},
+		{http.Header{"Cache-Control": {"public, max-age=600"}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, 0, 0, 10 * time.Minute, ExpiresMaxAge},
+		{http.Header{"Cache-Control": {"max-age=600", "s-maxage=60"}}, 0, 0, time.Minute, ExpiresSMaxAge},
+		{http.Header{"Cache-Control": {"max-age=600"}, "Age": {"100"}}, 0, 0, 500 * time.Second, ExpiresMaxAge},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"100"}}, 0, 0, 0, ExpiresMaxAge},
+		{http.Header{"Cache-Control": {"no-cache, max-age=600"}, "Date": {date}}, 0, 0, 0, ExpiresNoCache},
+		{http.Header{"Cache-Control": {"No-Store"}}, 0, 0, 0, ExpiresNoCache},
+		{http.Header{"Cache-Control": {"max-age=invalid"}}, 0, 0, defaultExpires, ExpiresDefault},
+		// invalid Expires header means the file has already expired
+		{http.Header{"Expires": {"0"}}, 0, 0, 0, ExpiresHeader},
+		{http.Header{"Expires": {"-1"}, "Date": {date}}, 0, 0, 0, ExpiresHeader},
+		{http.Header{"Expires": {"invalid"}}, 0, 0, 0, ExpiresHeader},
+		// expiration limits
+		{http.Header{"Cache-Control": {"no-cache"}}, time.Hour, 0, time.Hour, ExpiresNoCache},
+		{http.Header{"Cache-Control": {"max-age=31536000"}}, time.Hour, 24 * time.Hour, 24 * time.Hour, ExpiresMaxAge},
+		{http.Header{}, 0, 24 * time.Hour, 24 * time.Hour, ExpiresDefault},
+	}
+
+	for index, test := range tests {
+		c := NewCrawler(WithExpiresLimits(test.min, test.max))
+		res := &http.Response{Header: test.header, Request: httptest.NewRequest(http.MethodGet, "http://example.com/ads.txt", nil)}
+		expires, source := c.expires(res)
+
+		if source != test.source {
+			t.Errorf("Test #%d: expected expires source [%s] and not [%s]", index, test.source, source)
+		}
+		// allow for rounding of HTTP dates to seconds
+		if d := expires.Sub(now) - test.expected; d < -2*time.Second || d > 2*time.Second {
+			t.Errorf("Test #%d: expected expires in [%s] and not [%s]", index, test.expected, expires.Sub(now))
+		}
+	}
+}
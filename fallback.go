@@ -0,0 +1,87 @@
+package adstxt
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Fallback configure the URL variants tried when fetching a file from publisher root domain. Many publishers serve their
+// Ads.txt file only over HTTPS, or only on the "www." host of the root domain, without redirecting from other variants
+type Fallback struct {
+	Schemes []string // Schemes URL schemes to try, in order (i.e. "https", "http"). Empty list use the request URL scheme
+	WWW     bool     // WWW try the "www." host of the root domain after the bare root domain (and the other way around)
+}
+
+// DefaultFallback return fallback strategy that tries HTTPS and then HTTP, on the bare root domain and then on its "www." host
+func DefaultFallback() *Fallback {
+	return &Fallback{Schemes: []string{"https", "http"}, WWW: true}
+}
+
+// WithFallback set the crawler fallback strategy. By default only the request URL is fetched
+func WithFallback(f *Fallback) Option {
+	return func(c *Crawler) {
+		c.fallback = f
+	}
+}
+
+// FallbackAttempt holds the outcome of fetching single URL variant
+type FallbackAttempt struct {
+	URL        string `json:"url"`             // URL variant that was tried
+	StatusCode int    `json:"statusCode"`      // StatusCode of the remote host response, or 0 if no response was received
+	Error      string `json:"error,omitempty"` // Error fetching the URL variant, empty if the file was fetched successfully
+}
+
+// newFallbackAttempt return new failed FallbackAttempt
+func newFallbackAttempt(u string, err error) *FallbackAttempt {
+	a := &FallbackAttempt{URL: u, Error: err.Error()}
+
+	var e *Error
+	if errors.As(err, &e) {
+		a.StatusCode = e.StatusCode
+	}
+	return a
+}
+
+// variants return the request URL variants to try, in order. Host variants are tried only when the request URL host is the
+// root domain (or its "www." host), since the Ads.txt specification access method is defined for the root domain
+func (f *Fallback) variants(req *Request) []string {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return []string{req.URL}
+	}
+
+	// keep the request host port (if any) for all host variants
+	host, port := u.Hostname(), u.Port()
+	if len(port) > 0 {
+		port = ":" + port
+	}
+
+	hosts := []string{host}
+	if f.WWW {
+		root := strings.ToLower(req.Domain)
+		switch strings.ToLower(host) {
+		case root:
+			hosts = append(hosts, "www."+root)
+		case "www." + root:
+			hosts = []string{root, host}
+		}
+	}
+
+	schemes := f.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{u.Scheme}
+	}
+
+	variants := []string{}
+	for _, h := range hosts {
+		for _, s := range schemes {
+			v := *u
+			v.Scheme = s
+			v.Host = h + port
+			variants = append(variants, v.String())
+		}
+	}
+
+	return variants
+}
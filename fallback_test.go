@@ -0,0 +1,119 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestFallbackVariants test URL variants tried by fallback strategy
+func TestFallbackVariants(t *testing.T) {
+	f := DefaultFallback()
+
+	variants := map[string][]string{
+		"example.com": []string{
+			"https://example.com/ads.txt",
+			"http://example.com/ads.txt",
+			"https://www.example.com/ads.txt",
+			"http://www.example.com/ads.txt",
+		},
+		"https://www.example.com": []string{
+			"https://example.com/ads.txt",
+			"http://example.com/ads.txt",
+			"https://www.example.com/ads.txt",
+			"http://www.example.com/ads.txt",
+		},
+		"http://sub.example.com:8080": []string{
+			"https://sub.example.com:8080/ads.txt",
+			"http://sub.example.com:8080/ads.txt",
+		},
+	}
+
+	for rawurl, expected := range variants {
+		req, _ := NewRequest(rawurl)
+		v := f.variants(req)
+		if len(v) != len(expected) {
+			t.Errorf("Expected [%d] variants for [%s] and not [%d] %v", len(expected), rawurl, len(v), v)
+			continue
+		}
+		for i := range expected {
+			if v[i] != expected[i] {
+				t.Errorf("Expected variant #%d for [%s] to be [%s] and not [%s]", i, rawurl, expected[i], v[i])
+			}
+		}
+	}
+}
+
+// TestFallbackGet test crawler fall back to HTTP when Ads.txt file is not available over HTTPS
+func TestFallbackGet(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithFallback(DefaultFallback()))
+
+	req, _ := NewRequest(ts.URL)
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Variant != ts.URL+"/ads.txt" {
+		t.Errorf("Expected Ads.txt to be fetched from [%s] and not [%s]", ts.URL+"/ads.txt", res.Variant)
+	}
+
+	// HTTPS attempt fails, since test server is HTTP only
+	if len(res.Fallbacks) != 2 {
+		t.Fatalf("Expected 2 fallback attempts and not [%d]", len(res.Fallbacks))
+	}
+	if len(res.Fallbacks[0].Error) == 0 || len(res.Fallbacks[1].Error) > 0 || res.Fallbacks[1].StatusCode != http.StatusOK {
+		t.Errorf("Expected first attempt to fail and second attempt to succeed [%v] [%v]", res.Fallbacks[0], res.Fallbacks[1])
+	}
+}
+
+// TestFallbackNotFound test crawler return the last variant error when all variants fail, unless a variant host responded
+func TestFallbackNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithFallback(&Fallback{Schemes: []string{"http"}}))
+
+	req, _ := NewRequest(ts.URL)
+	if _, err := c.Get(req); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found error and not [%v]", err)
+	}
+}
+
+// TestFallbackNotFoundTransportError test crawler return not found error of the root domain when its "www." host fails
+// with transport error
+func TestFallbackNotFoundTransportError(t *testing.T) {
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Hostname() == "www.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: r.URL.Hostname(), IsNotFound: true}
+		}
+		return &http.Response{
+			Status:     "404 Not Found",
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    r,
+		}, nil
+	})
+
+	c := NewCrawler(WithRoundTripper(rt), WithFallback(&Fallback{Schemes: []string{"http"}, WWW: true}))
+
+	req, _ := NewRequest("example.com")
+	res, err := c.Get(req)
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrDNS) {
+		t.Errorf("Expected not found error and not [%v] [%v]", err, res)
+	}
+}
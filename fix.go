@@ -0,0 +1,175 @@
+package adstxt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// descriptions of the changes made by suggested fixes
+const (
+	fixSemicolons    = "replace semicolons with commas"
+	fixURLScheme     = "remove URL scheme and path from ad system domain"
+	fixCanonicalName = "use canonical ad system domain"
+	fixCanonicalForm = "normalize whitespace and account type"
+)
+
+// Fix machine-applicable suggested fix of Ads.txt line warning: replace the line content (without its comment) with
+// Replacement
+type Fix struct {
+	Replacement string `json:"replacement"` // Replacement line content
+	Description string `json:"description"` // Description of the changes made by the fix
+	Safe        bool   `json:"safe"`        // Safe fix does not change the meaning of the line, and is applied by ApplyFixes
+}
+
+// AppliedFix single change made by ApplyFixes
+type AppliedFix struct {
+	Index       int    `json:"index"`       // Index of the line in the Ads.txt file (starting from 1)
+	Old         string `json:"old"`         // Old line text
+	New         string `json:"new"`         // New line text
+	Description string `json:"description"` // Description of the change
+}
+
+// suggestFix return fix for data record line with warning w, or nil if the line can not be fixed mechanically
+func suggestFix(line string, w *Warning) *Fix {
+	changes := []string{}
+	safe := true
+
+	// data record fields separated by semicolons
+	if strings.Count(line, ",") < 2 && strings.Count(line, ";") >= 2 {
+		line = strings.Replace(line, ";", ",", -1)
+		changes = append(changes, fixSemicolons)
+	}
+
+	r := splitDataRecord(line)
+	if r == nil {
+		return nil
+	}
+
+	// ad system domain declared as URL (i.e. "https://google.com/")
+	if index := strings.Index(r.AdverterDomain, "://"); index != -1 {
+		r.AdverterDomain = strings.TrimRight(r.AdverterDomain[index+3:], "/")
+		if index := strings.Index(r.AdverterDomain, "/"); index != -1 {
+			r.AdverterDomain = r.AdverterDomain[:index]
+		}
+		changes = append(changes, fixURLScheme)
+	}
+
+	// known ad system domain that is not its canonical domain
+	lcDomain := strings.ToLower(r.AdverterDomain)
+	if canonical := normalizeAdSystemDomain(lcDomain); canonical != lcDomain {
+		r.AdverterDomain = canonical
+		changes = append(changes, fixCanonicalName+" "+canonical)
+		// ad system with several canonical domains, the first one may not be the right one
+		if strings.Contains(adSystems[adSystemDomains[lcDomain].ID].CanonicalDomain, ",") {
+			safe = false
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// the fix must resolve the warning, leaving the line valid or with low severity warning of different kind
+	replacement := formatDataRecord(r)
+	if _, fw := parseDataRecord(replacement); fw != nil && (fw.Level == HighSeverity || fw.Message == w.Message) {
+		return nil
+	}
+
+	return &Fix{Replacement: replacement, Description: strings.Join(changes, ", "), Safe: safe}
+}
+
+// ApplyFixes apply all safe suggested fixes to Ads.txt file, and fix data records with stray whitespace (around the line
+// or irregular whitespace around the fields separators) or lower-case account type. Fixed lines keep the fields separator
+// style of the file ("," or ", "). Return the corrected file and the list of changes made. Lines that were not changed
+// are kept byte-identical
+func ApplyFixes(b []byte) ([]byte, []*AppliedFix) {
+	f := ParseFile(b)
+	sep := fileSeparator(f)
+
+	applied := []*AppliedFix{}
+	for i, n := range f.Lines {
+		old := n.Text()
+
+		var description string
+		switch {
+		case n.Warning != nil && n.Warning.Fix != nil && n.Warning.Fix.Safe:
+			fix := n.Warning.Fix
+			n.SetContent(strings.Join(strings.Split(fix.Replacement, ", "), sep))
+			description = fix.Description
+		case n.DataRecord != nil && needsCanonicalForm(n):
+			lineSep := recordSeparator(n.Content)
+			if len(lineSep) == 0 {
+				lineSep = sep
+			}
+			fields := strings.Split(n.Content, ",")
+			for i := range fields {
+				fields[i] = strings.TrimSpace(fields[i])
+			}
+			fields[2] = strings.ToUpper(fields[2])
+			n.SetContent(strings.Join(fields, lineSep))
+			description = fixCanonicalForm
+		default:
+			continue
+		}
+
+		// remove stray whitespace around the fixed content
+		n.Leading = ""
+		if len(n.Comment) == 0 {
+			n.Trailing = ""
+		} else if len(n.Trailing) == 0 {
+			n.Trailing = " "
+		}
+
+		if n.Text() != old {
+			applied = append(applied, &AppliedFix{Index: i + 1, Old: old, New: n.Text(), Description: description})
+		}
+	}
+
+	return f.Bytes(), applied
+}
+
+// needsCanonicalForm return true if data record line has stray whitespace or lower-case account type
+func needsCanonicalForm(n *LineNode) bool {
+	if len(n.Leading) > 0 || (len(n.Comment) == 0 && len(n.Trailing) > 0) || len(recordSeparator(n.Content)) == 0 {
+		return true
+	}
+	accountType := strings.TrimSpace(strings.Split(n.Content, ",")[2])
+	return accountType != strings.ToUpper(accountType)
+}
+
+// recordSeparator return the separator of data record fields ("," or ", "), or empty string if the fields are not
+// separated consistently by one of them
+func recordSeparator(content string) string {
+	fields := strings.Split(content, ",")
+	sep := ""
+	for i, field := range fields {
+		if i < len(fields)-1 && strings.TrimRightFunc(field, unicode.IsSpace) != field {
+			return ""
+		}
+		if i == 0 {
+			continue
+		}
+
+		leading := field[:len(field)-len(strings.TrimLeftFunc(field, unicode.IsSpace))]
+		if (leading != "" && leading != " ") || (i > 1 && ","+leading != sep) {
+			return ""
+		}
+		sep = "," + leading
+	}
+	return sep
+}
+
+// fileSeparator return the data record fields separator used by most of the file data records, or ", " if the file
+// has no consistently separated data records
+func fileSeparator(f *File) string {
+	count := map[string]int{}
+	for _, n := range f.Lines {
+		if n.DataRecord != nil {
+			count[recordSeparator(n.Content)]++
+		}
+	}
+	if count[","] > count[", "] {
+		return ","
+	}
+	return ", "
+}
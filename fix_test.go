@@ -0,0 +1,108 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSuggestFix test suggested fix of parse warnings
+func TestSuggestFix(t *testing.T) {
+	tests := []struct {
+		line        string
+		replacement string
+	}{
+		{"googletagservices.com, pub-1234, DIRECT", "google.com, pub-1234, DIRECT"},
+		{"https://google.com/, pub-1234, DIRECT", "google.com, pub-1234, DIRECT"},
+		{"http://www.google.com/ads.txt, pub-1234, reseller", "www.google.com, pub-1234, RESELLER"},
+		{"google.com; pub-1234; direct; f08c47fec0942fa0", "google.com, pub-1234, DIRECT, f08c47fec0942fa0"},
+		{"googletagservices.com;pub-1234;DIRECT # comment", "google.com, pub-1234, DIRECT"},
+		{"google.com, pub-1234, OWNER", ""},
+		{"this is not a record", ""},
+	}
+
+	for _, test := range tests {
+		rec, err := ParseBody([]byte(test.line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rec.Warnings) != 1 {
+			t.Fatalf("Expected line [%s] to have [1] warning and not [%d]", test.line, len(rec.Warnings))
+		}
+
+		fix := rec.Warnings[0].Fix
+		if len(test.replacement) == 0 {
+			if fix != nil {
+				t.Errorf("Expected line [%s] to have no suggested fix and not [%+v]", test.line, fix)
+			}
+			continue
+		}
+		if fix == nil || fix.Replacement != test.replacement || !fix.Safe {
+			t.Errorf("Expected line [%s] suggested fix [%s] and not [%+v]", test.line, test.replacement, fix)
+		}
+	}
+}
+
+// TestApplyFixes test applying safe fixes to Ads.txt file
+func TestApplyFixes(t *testing.T) {
+	body := strings.Join([]string{
+		"# ads.txt",
+		"https://google.com, pub-1234, DIRECT # google",
+		"  appnexus.com ,1234,reseller",
+		"greenadexchange.com, XF7342, DIRECT",
+		"google.com, pub-1234, OWNER",
+		"CONTACT=adops@example.com",
+	}, "\r\n")
+
+	expected := strings.Join([]string{
+		"# ads.txt",
+		"google.com, pub-1234, DIRECT # google",
+		"appnexus.com, 1234, RESELLER",
+		"greenadexchange.com, XF7342, DIRECT",
+		"google.com, pub-1234, OWNER",
+		"CONTACT=adops@example.com",
+	}, "\r\n")
+
+	fixed, applied := ApplyFixes([]byte(body))
+	if string(fixed) != expected {
+		t.Errorf("Expected fixed file [%q] and not [%q]", expected, fixed)
+	}
+	if len(applied) != 2 || applied[0].Index != 2 || applied[0].Description != fixURLScheme ||
+		applied[1].Index != 3 || applied[1].Description != fixCanonicalForm {
+		t.Errorf("Expected [2] applied fixes and not [%d]", len(applied))
+	}
+	for _, a := range applied {
+		if a.Old == a.New {
+			t.Errorf("Expected applied fix to change the line and not [%+v]", a)
+		}
+	}
+
+	rec, err := ParseBody(fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Warnings) != 1 {
+		t.Errorf("Expected only unfixable warning to remain and not [%d] warnings", len(rec.Warnings))
+	}
+}
+
+// TestApplyFixesCompact test valid file with compact fields separators is not changed, and fixed lines keep its style
+func TestApplyFixesCompact(t *testing.T) {
+	body := "google.com,pub-1234,DIRECT,f08c47fec0942fa0\ngreenadexchange.com,XF7342,RESELLER # comment\nappnexus.com,1234,DIRECT\n"
+
+	fixed, applied := ApplyFixes([]byte(body))
+	if string(fixed) != body || len(applied) != 0 {
+		t.Errorf("Expected valid compact file to not be changed and not [%q] [%d] changes", fixed, len(applied))
+	}
+
+	fixed, applied = ApplyFixes([]byte(body + "https://google.com, pub-5678 ,reseller\n"))
+	expected := body + "google.com,pub-5678,RESELLER\n"
+	if string(fixed) != expected || len(applied) != 1 {
+		t.Errorf("Expected fixed line to keep the file separators [%q] and not [%q]", expected, fixed)
+	}
+
+	fixed, applied = ApplyFixes([]byte(body + "  appnexus.com,5678,reseller"))
+	expected = body + "appnexus.com,5678,RESELLER"
+	if string(fixed) != expected || len(applied) != 1 {
+		t.Errorf("Expected stray whitespace and account type to be fixed [%q] and not [%q]", expected, fixed)
+	}
+}
@@ -0,0 +1,260 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// FormatOption set optional Ads.txt formatter behaviour
+type FormatOption func(*formatOptions)
+
+// formatOptions Ads.txt formatter options
+type formatOptions struct {
+	sort bool // sort data records and variables
+}
+
+// SortRecords format option that group data records and variables, each group sorted: data records by ad system domain
+// and publisher account ID, and variables by their type. Comment lines directly above a record are moved with it, and
+// comments at the top of the file are kept as the file header
+func SortRecords() FormatOption {
+	return func(o *formatOptions) {
+		o.sort = true
+	}
+}
+
+// line kinds used by the formatter
+const (
+	lineBlank = iota
+	lineComment
+	lineDataRecord
+	lineVariable
+	lineOther
+)
+
+// formatLine single formatted Ads.txt line
+type formatLine struct {
+	kind     int
+	text     string   // text formatted line text
+	key      string   // key sort key of data record or variable line
+	comments []string // comments lines directly above the line, when sorting
+}
+
+// WriteRecords write the data records and variables as Ads.txt file: data records first, followed by the variables, one
+// record per line in canonical form (e.g. "greenadexchange.com, XF7342, DIRECT")
+func WriteRecords(w io.Writer, rec *Records) error {
+	bw := bufio.NewWriter(w)
+	for _, r := range rec.DataRecords {
+		bw.WriteString(formatDataRecord(r) + "\n")
+	}
+	for _, v := range rec.Variables {
+		bw.WriteString(formatVariable(v) + "\n")
+	}
+	return bw.Flush()
+}
+
+// Format return Ads.txt file in canonical form: fields whitespace is normalized, ad system domains are lower-cased,
+// account types and variable names are upper-cased, consecutive blank lines are collapsed and line endings are
+// converted to "\n". Comments are preserved, and lines that are not valid records are kept as they are
+func Format(body []byte, options ...FormatOption) ([]byte, error) {
+	o := &formatOptions{}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(body, utf8BOM)))
+	scanner.Split(scanLines)
+
+	lines := []*formatLine{}
+	for scanner.Scan() {
+		lines = append(lines, newFormatLine(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if o.sort {
+		lines = sortLines(lines)
+	}
+
+	var b bytes.Buffer
+	prev := lineBlank
+	for _, l := range lines {
+		// collapse consecutive blank lines, and remove blank lines at the top of the file
+		if l.kind == lineBlank && prev == lineBlank {
+			continue
+		}
+		for _, c := range l.comments {
+			b.WriteString(c + "\n")
+		}
+		b.WriteString(l.text + "\n")
+		prev = l.kind
+	}
+
+	// remove blank line at the end of the file
+	out := b.Bytes()
+	if prev == lineBlank && len(out) > 0 {
+		out = out[:len(out)-1]
+	}
+	return out, nil
+}
+
+// newFormatLine return Ads.txt line in canonical form
+func newFormatLine(txt string) *formatLine {
+	content := strings.TrimSpace(txt)
+	comment := ""
+	if index := strings.Index(content, commentDenote); index != -1 {
+		content, comment = strings.TrimSpace(content[:index]), strings.TrimSpace(content[index+len(commentDenote):])
+	}
+
+	if len(content) == 0 {
+		if len(strings.TrimSpace(txt)) == 0 {
+			return &formatLine{kind: lineBlank}
+		}
+		return &formatLine{kind: lineComment, text: strings.TrimSpace(txt)}
+	}
+
+	l := &formatLine{kind: lineOther, text: content}
+	if r := splitDataRecord(content); r != nil {
+		l.kind, l.text = lineDataRecord, formatDataRecord(r)
+		l.key = strings.ToLower(r.AdverterDomain) + "," + r.PublisherAccountID + "," + r.AccountType
+	} else if v := splitVariable(content); v != nil {
+		l.kind, l.text = lineVariable, formatVariable(v)
+		l.key = v.Type
+	}
+
+	if len(comment) > 0 {
+		l.text += " " + commentDenote + " " + comment
+	}
+	return l
+}
+
+// splitDataRecord return data record of line with the data record fields, without validating the fields values. Nil is
+// returned if the line is not a data record
+func splitDataRecord(line string) *DataRecord {
+	fields := strings.Split(line, ",")
+	if len(fields) < 3 || len(fields) > 4 {
+		return nil
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	accountType := strings.ToUpper(fields[2])
+	if len(fields[0]) == 0 || len(fields[1]) == 0 || (accountType != accountTypeDirect && accountType != accountTypeReseller) {
+		return nil
+	}
+
+	r := &DataRecord{AdverterDomain: fields[0], PublisherAccountID: fields[1], AccountType: accountType}
+	if len(fields) > 3 {
+		r.CertAuthorityID = fields[3]
+	}
+	return r
+}
+
+// splitVariable return variable of line with variable declaration, without validating the variable type and value. Nil
+// is returned if the line is not a variable declaration
+func splitVariable(line string) *Variable {
+	if strings.Count(line, "=") != 1 {
+		return nil
+	}
+
+	fields := strings.Split(line, "=")
+	v := &Variable{Type: strings.ToLower(strings.TrimSpace(fields[0])), Value: strings.TrimSpace(fields[1])}
+	if !variableNamePattern.MatchString(v.Type) {
+		return nil
+	}
+
+	if v.Type == varTypeManagerDomain {
+		if fields := strings.Split(v.Value, ","); len(fields) == 2 {
+			v.Value, v.CountryCode = strings.TrimSpace(fields[0]), strings.ToUpper(strings.TrimSpace(fields[1]))
+		}
+	}
+	return v
+}
+
+// sortLines group the lines (data records, variables and other lines), each group sorted. Comment lines are attached
+// to the line below them, and blank lines are replaced by a single blank line between groups
+func sortLines(lines []*formatLine) []*formatLine {
+	header := []*formatLine{}
+	groups := map[int][]*formatLine{}
+
+	comments := []string{}
+	for i, l := range lines {
+		switch l.kind {
+		case lineBlank:
+			// comments separated by blank line from the next record are not attached to it
+			for _, c := range comments {
+				groups[lineComment] = append(groups[lineComment], &formatLine{kind: lineComment, text: c})
+			}
+			comments = []string{}
+		case lineComment:
+			if len(groups[lineDataRecord])+len(groups[lineVariable])+len(groups[lineOther]) == 0 && isHeader(lines[i:]) {
+				header = append(header, l)
+				continue
+			}
+			comments = append(comments, l.text)
+		default:
+			l.comments = comments
+			comments = []string{}
+			groups[l.kind] = append(groups[l.kind], l)
+		}
+	}
+	for _, c := range comments {
+		groups[lineComment] = append(groups[lineComment], &formatLine{kind: lineComment, text: c})
+	}
+
+	sort.SliceStable(groups[lineDataRecord], func(i, j int) bool {
+		return groups[lineDataRecord][i].key < groups[lineDataRecord][j].key
+	})
+	sort.SliceStable(groups[lineVariable], func(i, j int) bool {
+		return groups[lineVariable][i].key < groups[lineVariable][j].key
+	})
+
+	sorted := header
+	for _, kind := range []int{lineDataRecord, lineVariable, lineOther, lineComment} {
+		if len(groups[kind]) == 0 {
+			continue
+		}
+		if len(sorted) > 0 {
+			sorted = append(sorted, &formatLine{kind: lineBlank})
+		}
+		sorted = append(sorted, groups[kind]...)
+	}
+	return sorted
+}
+
+// isHeader return true if the comment lines at the top of the file are followed by blank line, and are not attached to
+// the first record
+func isHeader(lines []*formatLine) bool {
+	for _, l := range lines {
+		if l.kind != lineComment {
+			return l.kind == lineBlank
+		}
+	}
+	return true
+}
+
+// formatDataRecord return data record as Ads.txt line in canonical form
+func formatDataRecord(r *DataRecord) string {
+	fields := []string{
+		strings.ToLower(strings.TrimSpace(r.AdverterDomain)),
+		strings.TrimSpace(r.PublisherAccountID),
+		strings.ToUpper(strings.TrimSpace(r.AccountType)),
+	}
+	if len(r.CertAuthorityID) > 0 {
+		fields = append(fields, strings.TrimSpace(r.CertAuthorityID))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// formatVariable return variable as Ads.txt line in canonical form
+func formatVariable(v *Variable) string {
+	if len(v.CountryCode) > 0 {
+		return fmt.Sprintf("%s=%s,%s", strings.ToUpper(v.Type), v.Value, v.CountryCode)
+	}
+	return fmt.Sprintf("%s=%s", strings.ToUpper(v.Type), v.Value)
+}
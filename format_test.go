@@ -0,0 +1,119 @@
+package adstxt
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestWriteRecords test writing records as Ads.txt file, and parsing it back
+func TestWriteRecords(t *testing.T) {
+	body := strings.Join([]string{
+		"CONTACT=adops@example.com",
+		"Google.com ,pub-1234,direct, f08c47fec0942fa0",
+		"greenadexchange.com, XF7342, RESELLER # comment",
+		"MANAGERDOMAIN=example.net,us",
+	}, "\n")
+	rec, err := ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := WriteRecords(&b, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0",
+		"greenadexchange.com, XF7342, RESELLER",
+		"CONTACT=adops@example.com",
+		"MANAGERDOMAIN=example.net,US",
+	}, "\n") + "\n"
+	if b.String() != expected {
+		t.Errorf("Expected Ads.txt file [%s] and not [%s]", expected, b.String())
+	}
+
+	parsed, err := ParseBody(b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Warnings) != 0 || !reflect.DeepEqual(parsed.Variables, rec.Variables) || len(parsed.DataRecords) != len(rec.DataRecords) {
+		t.Errorf("Expected written file to be parsed to the same records and not [%s]", parsed)
+	}
+}
+
+// TestFormat test Ads.txt file canonical formatting
+func TestFormat(t *testing.T) {
+	body := strings.Join([]string{
+		"  # ads.txt file for example.com",
+		"",
+		"",
+		"GreenAdExchange.com ,XF7342,reseller  #  inline comment",
+		"google.com,\tpub-1234 , Direct,f08c47fec0942fa0",
+		"contact = adops@example.com",
+		"this is not a record",
+		"",
+	}, "\r\n")
+
+	expected := strings.Join([]string{
+		"# ads.txt file for example.com",
+		"",
+		"greenadexchange.com, XF7342, RESELLER # inline comment",
+		"google.com, pub-1234, DIRECT, f08c47fec0942fa0",
+		"CONTACT=adops@example.com",
+		"this is not a record",
+	}, "\n") + "\n"
+
+	out, err := Format([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != expected {
+		t.Errorf("Expected formatted file [%s] and not [%s]", expected, out)
+	}
+
+	// formatting is idempotent
+	again, _ := Format(out)
+	if !bytes.Equal(again, out) {
+		t.Errorf("Expected formatted file to not change when formatted again and not [%s]", again)
+	}
+}
+
+// TestFormatSortRecords test sorting and grouping Ads.txt file lines
+func TestFormatSortRecords(t *testing.T) {
+	body := strings.Join([]string{
+		"# ads.txt file for example.com",
+		"",
+		"OWNERDOMAIN=example.com",
+		"# google account",
+		"google.com, pub-1234, DIRECT",
+		"",
+		"CONTACT=adops@example.com",
+		"appnexus.com, 1234, RESELLER",
+		"",
+		"# end of file",
+	}, "\n")
+
+	expected := strings.Join([]string{
+		"# ads.txt file for example.com",
+		"",
+		"appnexus.com, 1234, RESELLER",
+		"# google account",
+		"google.com, pub-1234, DIRECT",
+		"",
+		"CONTACT=adops@example.com",
+		"OWNERDOMAIN=example.com",
+		"",
+		"# end of file",
+	}, "\n") + "\n"
+
+	out, err := Format([]byte(body), SortRecords())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != expected {
+		t.Errorf("Expected sorted file [%s] and not [%s]", expected, out)
+	}
+}
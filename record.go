@@ -26,8 +26,20 @@ const (
 	varTypeSubdomain = "subdomain"
 	// Contact information for the owner of the Ads.txt file
 	varTypeContact = "contact"
+	// InventoryPartnerDomain points to the Ads.txt file of an inventory partner (CTV/app inventory sharing, Ads.txt 1.1)
+	varTypeInventoryPartnerDomain = "inventorypartnerdomain"
+	// OwnerDomain specifies the business domain of the owner of the Ads.txt file (Ads.txt 1.1)
+	varTypeOwnerDomain = "ownerdomain"
+	// ManagerDomain specifies the business domain of a primary or exclusive monetization partner, optionally per country (Ads.txt 1.1)
+	varTypeManagerDomain = "managerdomain"
 )
 
+// variableNamePattern matches a well formed variable name, used to distinguish unknown variables from garbage lines
+var variableNamePattern = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_-]*$")
+
+// countryCodePattern matches ISO 3166-1 alpha-2 country code used by MANAGERDOMAIN variable
+var countryCodePattern = regexp.MustCompile("^[a-zA-Z]{2}$")
+
 // DataRecord hold single Ads.txt data record
 type DataRecord struct {
 	AdverterDomain     string `json:"adverterdomain"`            // AdverterDomain Domain name of the advertising system (required)
@@ -38,8 +50,9 @@ type DataRecord struct {
 
 // Variable hold single of Ads.txt variable record
 type Variable struct {
-	Type  string `json:"type"`  // Type of variable record. Supported types are subdomain and contact
-	Value string `json:"value"` // Value of variable record
+	Type        string `json:"type"`                  // Type of variable record. Supported types are subdomain, contact, inventorypartnerdomain, ownerdomain and managerdomain
+	Value       string `json:"value"`                 // Value of variable record
+	CountryCode string `json:"countrycode,omitempty"` // CountryCode optional ISO 3166-1 alpha-2 country code of managerdomain variable
 }
 
 // parseDataRecord return new DataRecord parsed from single Ads.txt line
@@ -114,23 +127,75 @@ func parseVariable(line string) (*Variable, *Warning) {
 	fields := strings.Split(line, "=")
 
 	// check that record type is supported, and return new variable of that type
-	t := fields[0]
+	t := strings.TrimSpace(fields[0])
+	value := strings.TrimSpace(fields[1])
 	switch strings.ToLower(t) {
 	case varTypeSubdomain:
 		return &Variable{
 			Type:  varTypeSubdomain,
-			Value: fields[1],
+			Value: value,
 		}, nil
 	case varTypeContact:
 		return &Variable{
 			Type:  varTypeContact,
-			Value: fields[1],
+			Value: value,
+		}, nil
+	case varTypeInventoryPartnerDomain, varTypeOwnerDomain:
+		if !validateDomainName(value) {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("%s is not a valid %s domain", value, strings.ToLower(t))}
+		}
+		return &Variable{
+			Type:  strings.ToLower(t),
+			Value: value,
 		}, nil
+	case varTypeManagerDomain:
+		return parseManagerDomain(value)
 	default:
+		// According to IAB ads.txt specification version 1.1, section 3.5 "VARIABLE DECLARATION RECORDS":
+		// unknown variables should be ignored, so well formed unknown variable is reported only as a low severity warning
+		if variableNamePattern.MatchString(t) {
+			return nil, &Warning{Level: LowSeverity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
+		}
 		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
 	}
 }
 
+// isVariableType return true if name (case insensitive) is a supported Variable type
+func isVariableType(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case varTypeSubdomain, varTypeContact, varTypeInventoryPartnerDomain, varTypeOwnerDomain, varTypeManagerDomain:
+		return true
+	}
+	return false
+}
+
+// parseManagerDomain return new managerdomain Variable record parsed from managerdomain variable value.
+// Manager domain declaration: MANAGERDOMAIN=<domain>[,<country code>]
+func parseManagerDomain(value string) (*Variable, *Warning) {
+	fields := strings.Split(value, ",")
+	if len(fields) > 2 {
+		return nil, &Warning{Level: HighSeverity, Message: "managerdomain must be declared as <domain>,<country code> (optional) pattern"}
+	}
+
+	domain := strings.TrimSpace(fields[0])
+	if !validateDomainName(domain) {
+		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("%s is not a valid %s domain", domain, varTypeManagerDomain)}
+	}
+
+	v := &Variable{Type: varTypeManagerDomain, Value: domain}
+
+	// optional value
+	if len(fields) > 1 {
+		countryCode := strings.TrimSpace(fields[1])
+		if !countryCodePattern.MatchString(countryCode) {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid ISO 3166-1 alpha-2 country code", countryCode)}
+		}
+		v.CountryCode = strings.ToUpper(countryCode)
+	}
+
+	return v, nil
+}
+
 // removeComment removes any comment from Ads.txt line before parsing
 func removeComment(line string) string {
 	index := strings.Index(line, commentDenote)
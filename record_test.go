@@ -278,6 +278,55 @@ func TestParseNotSupportedVariable(t *testing.T) {
 	}
 }
 
+// TestParseAdsTxt11Variables test parsing Ads.txt version 1.1 Variable types
+func TestParseAdsTxt11Variables(t *testing.T) {
+	variables := map[string]Variable{
+		"OWNERDOMAIN=example.com":                   Variable{Type: varTypeOwnerDomain, Value: "example.com"},
+		"MANAGERDOMAIN=manager.com":                 Variable{Type: varTypeManagerDomain, Value: "manager.com"},
+		"managerdomain=manager.com, us":             Variable{Type: varTypeManagerDomain, Value: "manager.com", CountryCode: "US"},
+		"INVENTORYPARTNERDOMAIN=partner.example.tv": Variable{Type: varTypeInventoryPartnerDomain, Value: "partner.example.tv"},
+	}
+
+	for line, expected := range variables {
+		v, w := parseVariable(line)
+		if w != nil {
+			t.Errorf("Expected no errors when parsing [%s] [%v]", line, w)
+			continue
+		}
+		if *v != expected {
+			t.Errorf("Expected variable for [%s] to be [%v] but received [%v]", line, expected, *v)
+		}
+	}
+
+	// invalid variable values
+	lines := []string{
+		"OWNERDOMAIN=http://example.com",
+		"INVENTORYPARTNERDOMAIN=example.com/path",
+		"MANAGERDOMAIN=manager.com,USA",
+		"MANAGERDOMAIN=manager.com,US,extra",
+	}
+
+	for _, line := range lines {
+		v, w := parseVariable(line)
+		if w == nil || w.Level != HighSeverity {
+			t.Errorf("Expected high severity warning when parsing [%s] [%v]", line, v)
+		}
+	}
+}
+
+// TestParseUnknownVariableSeverity test unknown but well formed Variable type is reported as low severity warning
+func TestParseUnknownVariableSeverity(t *testing.T) {
+	_, w := parseVariable("futurevariable=value")
+	if w == nil || w.Level != LowSeverity {
+		t.Errorf("Expected low severity warning for unknown variable type but received [%v]", w)
+	}
+
+	_, w = parseVariable("not a variable=value")
+	if w == nil || w.Level != HighSeverity {
+		t.Errorf("Expected high severity warning for malformed variable type but received [%v]", w)
+	}
+}
+
 // TestRemoveComment test creating new line with Ads.txt comment
 func TestRemoveComment(t *testing.T) {
 	s := "advertising.com,17429, DIRECT, #video, US"
@@ -0,0 +1,110 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redirect policy errors
+const (
+	errRedirectLoop            = "[%s] failed to get Ads.txt file, redirect loop from [%s] back to [%s]"
+	errRedirectOutOfDomain     = "[%s] redirect from [%s] to [%s] outside the original root domain is not allowed by strict redirect policy"
+	errRedirectSchemeDowngrade = "[%s] redirect from [%s] to [%s] downgrade HTTPS to HTTP and is not allowed by strict redirect policy"
+)
+
+// RedirectPolicy decide whether the crawler follows HTTP redirect. Regardless of the policy, the crawler stops on redirect
+// loops and after the maximum number of redirects (see WithMaxRedirects)
+type RedirectPolicy interface {
+	// CheckRedirect is called before following redirect to the redirect URL. hops holds the HTTP requests sent so far, the last
+	// one is the redirect response (with its OutOfDomain flag set). Returning an error stop crawling, returning a warning
+	// follow the redirect and add the warning to the response warnings
+	CheckRedirect(req *Request, hops []*Hop, redirect string) (*Warning, error)
+}
+
+// RedirectPolicyFunc is an adapter to allow the use of ordinary functions as RedirectPolicy
+type RedirectPolicyFunc func(req *Request, hops []*Hop, redirect string) (*Warning, error)
+
+// CheckRedirect calls f(req, hops, redirect)
+func (f RedirectPolicyFunc) CheckRedirect(req *Request, hops []*Hop, redirect string) (*Warning, error) {
+	return f(req, hops, redirect)
+}
+
+// redirectMode built-in redirect policy mode
+type redirectMode int
+
+const (
+	redirectIAB        redirectMode = iota // follow IAB ads.txt specification rules
+	redirectStrict                         // IAB rules, and redirects must stay within the root domain over the same scheme
+	redirectPermissive                     // IAB rules, but redirects to other paths are followed with a warning
+)
+
+// redirectPolicy built-in RedirectPolicy
+type redirectPolicy struct {
+	mode redirectMode
+}
+
+// IABRedirectPolicy return the default redirect policy, following IAB ads.txt specification section 3.1 "ACCESS METHOD":
+// multiple redirects within the original root domain and single redirect outside of it are allowed, and each redirect
+// must point to a file with the requested file name
+func IABRedirectPolicy() RedirectPolicy {
+	return &redirectPolicy{mode: redirectIAB}
+}
+
+// StrictRedirectPolicy return redirect policy that follow IAB rules, but does not allow redirects outside the original root
+// domain or from HTTPS to HTTP
+func StrictRedirectPolicy() RedirectPolicy {
+	return &redirectPolicy{mode: redirectStrict}
+}
+
+// PermissiveRedirectPolicy return redirect policy that follow IAB root domain rules, but allows redirects to URL that is not
+// the requested file (i.e. "/ads.txt" redirect to "/ads") and report them as warning
+func PermissiveRedirectPolicy() RedirectPolicy {
+	return &redirectPolicy{mode: redirectPermissive}
+}
+
+// WithRedirectPolicy set the crawler redirect policy. By default IABRedirectPolicy is used
+func WithRedirectPolicy(p RedirectPolicy) Option {
+	return func(c *Crawler) {
+		c.redirectPolicy = p
+	}
+}
+
+// CheckRedirect is the RedirectPolicy interface implementation for built-in redirect policies
+func (p *redirectPolicy) CheckRedirect(req *Request, hops []*Hop, redirect string) (*Warning, error) {
+	hop := hops[len(hops)-1]
+
+	if hop.OutOfDomain {
+		if p.mode == redirectStrict {
+			return nil, fmt.Errorf(errRedirectOutOfDomain, req.Domain, hop.URL, redirect)
+		}
+
+		// If redirect to different domain, check that this is the first redirect to different domain
+		// According to IAB ads.txt specification, section 3.1 "ACCESS METHOD":
+		// "Only a single HTTP redirect to a destination outside the original root domain is allowed to
+		// facilitate one-hop delegation of authority to a third party's web server domain."
+		d, _ := rootDomain(redirect)
+		prevDomain, _ := rootDomain(hop.URL)
+		if prevDomain != req.Domain && prevDomain != d {
+			return nil, fmt.Errorf(errRedirectToDifferentDomain, req.Domain, prevDomain, d)
+		}
+	}
+
+	if p.mode == redirectStrict {
+		from, err1 := url.Parse(hop.URL)
+		to, err2 := url.Parse(redirect)
+		if err1 == nil && err2 == nil && from.Scheme == "https" && to.Scheme != "https" {
+			return nil, fmt.Errorf(errRedirectSchemeDowngrade, req.Domain, hop.URL, redirect)
+		}
+	}
+
+	// make sure redirects takes us to another Ads.txt (or app-ads.txt) file and not just to home page
+	if !strings.HasSuffix(redirect, "/"+req.fileName()) {
+		if p.mode == redirectPermissive {
+			return &Warning{Text: redirect, Level: HighSeverity, Message: fmt.Sprintf(errRedirectToInvalidAdsTxt, req.Domain, hop.URL, redirect)}, nil
+		}
+		return nil, fmt.Errorf(errRedirectToInvalidAdsTxt, req.Domain, hop.URL, redirect)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRedirectPolicies test the built-in redirect policies
+func TestRedirectPolicies(t *testing.T) {
+	req, _ := NewRequest("https://example.com")
+
+	tests := []struct {
+		policy   RedirectPolicy
+		hop      *Hop
+		redirect string
+		warning  bool
+		err      bool
+	}{
+		// IAB policy
+		{IABRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt"}, "https://www.example.com/ads.txt", false, false},
+		{IABRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt", OutOfDomain: true}, "https://other.com/ads.txt", false, false},
+		{IABRedirectPolicy(), &Hop{URL: "https://other.com/ads.txt", OutOfDomain: true}, "https://third.com/ads.txt", false, true},
+		{IABRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt"}, "https://example.com/", false, true},
+		// strict policy
+		{StrictRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt"}, "https://www.example.com/ads.txt", false, false},
+		{StrictRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt", OutOfDomain: true}, "https://other.com/ads.txt", false, true},
+		{StrictRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt"}, "http://example.com/ads.txt", false, true},
+		// permissive policy
+		{PermissiveRedirectPolicy(), &Hop{URL: "https://example.com/ads.txt"}, "https://example.com/ads", true, false},
+		{PermissiveRedirectPolicy(), &Hop{URL: "https://other.com/ads.txt", OutOfDomain: true}, "https://third.com/ads.txt", false, true},
+	}
+
+	for _, test := range tests {
+		w, err := test.policy.CheckRedirect(req, []*Hop{test.hop}, test.redirect)
+		if (err != nil) != test.err {
+			t.Errorf("Expected redirect from [%s] to [%s] error to be [%t] and not [%v]", test.hop.URL, test.redirect, test.err, err)
+		}
+		if (w != nil) != test.warning {
+			t.Errorf("Expected redirect from [%s] to [%s] warning to be [%t] and not [%v]", test.hop.URL, test.redirect, test.warning, w)
+		}
+	}
+}
+
+// TestRedirectPolicyWarning test redirect warning is added to the response warnings
+func TestRedirectPolicyWarning(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ads.txt" {
+			w.Header().Set("Location", "/ads")
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	req, _ := NewRequest(ts.URL)
+
+	if _, err := NewCrawler().Get(req); !errors.Is(err, ErrRedirect) {
+		t.Errorf("Expected default redirect policy to fail with [%s] and not [%v]", ErrRedirect, err)
+	}
+
+	res, err := NewCrawler(WithRedirectPolicy(PermissiveRedirectPolicy())).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected [1] data record and not [%d]", len(res.DataRecords))
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Index != 0 || res.Warnings[0].Text != ts.URL+"/ads" {
+		t.Errorf("Expected single redirect warning and not [%v]", res.Warnings)
+	}
+}
+
+// TestRedirectLoop test the crawler stop on redirect loop regardless of the redirect policy
+func TestRedirectLoop(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ads.txt" {
+			w.Header().Set("Location", "/www/ads.txt")
+		} else {
+			w.Header().Set("Location", "/ads.txt")
+		}
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer ts.Close()
+
+	allowAll := RedirectPolicyFunc(func(req *Request, hops []*Hop, redirect string) (*Warning, error) {
+		return nil, nil
+	})
+	c := NewCrawler(WithRedirectPolicy(allowAll))
+
+	req, _ := NewRequest(ts.URL)
+	_, err := c.Get(req)
+
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrRedirect {
+		t.Fatalf("Expected redirect loop to fail with [%s] and not [%v]", ErrRedirect, err)
+	}
+	if e.URL != ts.URL+"/www/ads.txt" {
+		t.Errorf("Expected redirect loop to fail at [%s] and not [%s]", ts.URL+"/www/ads.txt", e.URL)
+	}
+}
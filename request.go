@@ -6,14 +6,44 @@ import (
 	"strings"
 )
 
+// FileType of the file requested from remote host: Ads.txt for web inventory, app-ads.txt for mobile and CTV inventory or
+// sellers.json of an advertising system
+type FileType string
+
+const (
+	// AdsTxt Ads.txt file posted on publisher root domain (web inventory)
+	AdsTxt FileType = "ads.txt"
+	// AppAdsTxt app-ads.txt file posted on app developer domain (mobile and CTV inventory)
+	AppAdsTxt FileType = "app-ads.txt"
+	// SellersJSON sellers.json file posted on advertising system domain
+	SellersJSON FileType = "sellers.json"
+)
+
 // Request to fetch Ads.txt file from remote host
 type Request struct {
-	Domain string `json:"domain"` // Domain holds the root domain of the remote host
-	URL    string `json:"url"`    // URL of the Ads.txt file to fetch
+	Domain   string   `json:"domain"`   // Domain holds the root domain of the remote host
+	URL      string   `json:"url"`      // URL of the Ads.txt file to fetch
+	FileType FileType `json:"filetype"` // FileType of the requested file (ads.txt, app-ads.txt or sellers.json)
 }
 
 // NewRequest create new Ads.txt file request from remote host
 func NewRequest(rawurl string) (*Request, error) {
+	return newRequest(rawurl, AdsTxt)
+}
+
+// NewAppRequest create new app-ads.txt file request from app developer URL (as listed in the app store). The file is
+// requested from the root of the developer URL host, the URL path and query are ignored
+func NewAppRequest(developerURL string) (*Request, error) {
+	return newRequest(developerURL, AppAdsTxt)
+}
+
+// NewSellersRequest create new sellers.json file request from advertising system domain
+func NewSellersRequest(adSystemDomain string) (*Request, error) {
+	return newRequest(adSystemDomain, SellersJSON)
+}
+
+// newRequest create new request for Ads.txt or app-ads.txt file from remote host
+func newRequest(rawurl string, fileType FileType) (*Request, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
 		return nil, err
@@ -25,9 +55,19 @@ func NewRequest(rawurl string) (*Request, error) {
 		u.Scheme = "http"
 	}
 
-	// add "/ads.txt" to URL path
-	if !strings.HasSuffix(u.Path, "/ads.txt") {
-		u.Path = fmt.Sprintf("%s/ads.txt", strings.TrimSuffix(u.Path, "/"))
+	// add "/ads.txt" (or "/app-ads.txt") to URL path
+	path := "/" + string(fileType)
+	switch {
+	case fileType == AppAdsTxt:
+		// app-ads.txt is posted on the root of the developer domain, so the developer URL path (i.e. the app page) is dropped
+		if len(u.Host) == 0 {
+			if u, err = url.Parse(u.Scheme + "://" + rawurl); err != nil {
+				return nil, err
+			}
+		}
+		u.Path, u.RawPath, u.RawQuery, u.Fragment, u.User = path, "", "", "", nil
+	case !strings.HasSuffix(u.Path, path):
+		u.Path = fmt.Sprintf("%s%s", strings.TrimSuffix(u.Path, "/"), path)
 	}
 
 	// Publishers should post the "/ads.txt" file on their root domain and any subdomains as needed.
@@ -38,5 +78,21 @@ func NewRequest(rawurl string) (*Request, error) {
 	}
 
 	adsTxtURL := fmt.Sprintf("%v", u)
-	return &Request{URL: adsTxtURL, Domain: d}, nil
+	return &Request{URL: adsTxtURL, Domain: d, FileType: fileType}, nil
+}
+
+// fileName return the name of the requested file, requests created without file type default to Ads.txt
+func (r *Request) fileName() string {
+	if len(r.FileType) == 0 {
+		return string(AdsTxt)
+	}
+	return string(r.FileType)
+}
+
+// contentType return the expected content type of the requested file
+func (r *Request) contentType() string {
+	if r.FileType == SellersJSON {
+		return "application/json"
+	}
+	return "text/plain"
 }
@@ -29,3 +29,26 @@ func TestNewRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestNewAppRequest(t *testing.T) {
+	domains := map[string]Request{
+		"example.com":                 Request{URL: "http://example.com/app-ads.txt", Domain: "example.com"},
+		"https://www.example.com/":    Request{URL: "https://www.example.com/app-ads.txt", Domain: "example.com"},
+		"http://dev.example.com/apps": Request{URL: "http://dev.example.com/app-ads.txt", Domain: "example.com"},
+		"dev.example.com/apps?id=1":   Request{URL: "http://dev.example.com/app-ads.txt", Domain: "example.com"},
+		"https://play.example.com/store/apps/details?id=com.example#top": Request{URL: "https://play.example.com/app-ads.txt", Domain: "example.com"},
+		"https://example.com/app-ads.txt":                                Request{URL: "https://example.com/app-ads.txt", Domain: "example.com"}}
+
+	for k, v := range domains {
+		r, _ := NewAppRequest(k)
+		if r.URL != v.URL {
+			t.Errorf("Expected app-ads.txt for [%s] to be [%s] but received [%s]", k, v.URL, r.URL)
+		}
+		if r.Domain != v.Domain {
+			t.Errorf("Expected Domain for [%s] to be [%s] but received [%s]", k, v.Domain, r.Domain)
+		}
+		if r.FileType != AppAdsTxt {
+			t.Errorf("Expected file type for [%s] to be [%s] but received [%s]", k, AppAdsTxt, r.FileType)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package adstxt
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -17,63 +18,114 @@ type Records struct {
 }
 
 // Response to an Ads.txt request: collection of Data\Variable records parsed from Ads.txt file and
-// file expiration date
+// file expiration date. The type of the file (ads.txt or app-ads.txt) is available using the embedded Request FileType
 type Response struct {
 	*Request
 	*Records
-	Expires time.Time `json:"expires"` // Ads.txt file expiration date
+	Expires       time.Time          `json:"expires"`       // Ads.txt file expiration date
+	ExpiresSource ExpiresSource      `json:"expiresSource"` // ExpiresSource the source of the expiration date (caching header or default)
+	Attempts      int                `json:"attempts"`      // Attempts number of HTTP attempts made to fetch the file (more than 1 if failed requests were retried)
+	Variant       string             `json:"variant"`       // Variant URL variant from which the file was fetched (before redirects)
+	Fallbacks     []*FallbackAttempt `json:"fallbacks"`     // Fallbacks outcome of each URL variant tried, in order
+	FinalURL      string             `json:"finalUrl"`      // FinalURL the file was fetched from, after following redirects
+	Hops          []*Hop             `json:"hops"`          // Hops HTTP requests sent to fetch the file, the last one is the final URL
+	Header        http.Header        `json:"header"`        // Header of the final HTTP response
+	BodySize      int64              `json:"bodySize"`      // BodySize number of bytes read from the response body
+	CacheStatus   CacheStatus        `json:"cacheStatus"`   // CacheStatus how the response was served, when the crawler has a cache
 }
 
-// parseRecords parse Ads.txt file content
-func parseRecords(lines []string) *Records {
-	r := &Records{
+// Line single line of Ads.txt file and the Data\Variable record parsed from it. Comments and empty lines have no record
+type Line struct {
+	Index      int         // Index of the line in the Ads.txt file (starting from 1)
+	Text       string      // Text of the line
+	DataRecord *DataRecord // DataRecord parsed from the line, if any
+	Variable   *Variable   // Variable parsed from the line, if any
+	Warning    *Warning    // Warning found parsing the line, if any
+}
+
+// newRecords create new empty Records
+func newRecords() *Records {
+	return &Records{
 		DataRecords: []*DataRecord{},
 		Variables:   []*Variable{},
 		Warnings:    []*Warning{},
-		Body:        lines,
+		Body:        []string{},
 	}
+}
 
-	// loop over Ads.txt file lines and parse each line into Ads.txt record
-	for index, l := range lines {
-		r.parseRecord(index+1, l)
+// add parsed line to the records
+func (r *Records) add(l *Line) {
+	if l.DataRecord != nil {
+		r.DataRecords = append(r.DataRecords, l.DataRecord)
+	}
+	if l.Variable != nil {
+		r.Variables = append(r.Variables, l.Variable)
+	}
+	if l.Warning != nil {
+		r.Warnings = append(r.Warnings, l.Warning)
 	}
-
-	return r
 }
 
-// parseRecord parse a single Ads.txt line into Data\Variable record
-func (r *Records) parseRecord(index int, txt string) {
+// parseLine parse a single Ads.txt line into Data\Variable record. Variables are validated against the Variables
+// already added to the records
+func (r *Records) parseLine(index int, txt string) *Line {
+	l := &Line{Index: index, Text: txt}
 	line := removeComment(txt)
 
 	// ignore comments and empty line
 	if len(line) == 0 || string(line) == commentDenote {
-		return
+		return l
 	}
 
-	// parse line into Data\Variable record
-	if strings.Count(line, ",") >= 2 && strings.Count(line, "=") <= 5 {
-		dr, w := parseDataRecord(line)
-		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
+	// parse line into Data\Variable record. Declaration of known variable is parsed as variable even if its value has
+	// commas (i.e. MANAGERDOMAIN=<domain>,<country code>)
+	variable := strings.Count(line, "=") == 1
+	if !(variable && isVariableType(line[:strings.Index(line, "=")])) && strings.Count(line, ",") >= 2 && strings.Count(line, "=") <= 5 {
+		l.DataRecord, l.Warning = parseDataRecord(line)
+	} else if variable {
+		l.Variable, l.Warning = parseVariable(line)
+		if l.Warning == nil && l.Variable != nil {
+			l.Warning = r.validateVariable(l.Variable)
 		}
-		if dr != nil {
-			r.DataRecords = append(r.DataRecords, dr)
+	} else {
+		l.Warning = &Warning{Level: HighSeverity, Message: "could not parse this line"}
+	}
+
+	if l.Warning != nil {
+		l.Warning.Index = index
+		l.Warning.Text = txt
+		if l.Variable == nil {
+			l.Warning.Fix = suggestFix(line, l.Warning)
 		}
-	} else if strings.Index(line, "=") != -1 && strings.Count(line, "=") == 1 {
-		v, w := parseVariable(txt)
-		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
-		} else {
-			r.Variables = append(r.Variables, v)
+	}
+
+	return l
+}
+
+// validateVariable check a newly parsed Variable against the Variables already parsed from the same Ads.txt file
+func (r *Records) validateVariable(v *Variable) *Warning {
+	for _, prev := range r.Variables {
+		if prev.Type != v.Type {
+			continue
+		}
+
+		switch v.Type {
+		// the specification allows a single OWNERDOMAIN declaration in Ads.txt file
+		case varTypeOwnerDomain:
+			return &Warning{Level: HighSeverity, Message: fmt.Sprintf("Multiple %s declarations are not allowed, %s is already declared", varTypeOwnerDomain, prev.Value)}
+		// the specification allows a single global MANAGERDOMAIN declaration, and a single MANAGERDOMAIN declaration per country
+		case varTypeManagerDomain:
+			if prev.CountryCode == v.CountryCode {
+				if len(v.CountryCode) == 0 {
+					return &Warning{Level: HighSeverity, Message: fmt.Sprintf("Multiple %s declarations are not allowed, %s is already declared", varTypeManagerDomain, prev.Value)}
+				}
+				return &Warning{Level: HighSeverity, Message: fmt.Sprintf("Multiple %s declarations for country [%s] are not allowed, %s is already declared",
+					varTypeManagerDomain, v.CountryCode, prev.Value)}
+			}
 		}
-	} else {
-		w := &Warning{Text: txt, Index: index, Level: HighSeverity, Message: "could not parse this line"}
-		r.Warnings = append(r.Warnings, w)
 	}
+
+	return nil
 }
 
 // custom "toString" method
@@ -0,0 +1,176 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configure how a crawler retries HTTP requests that failed with a transient error. Failed requests are retried
+// with exponential backoff and random jitter, and for 429 (Too Many Requests) and 503 (Service Unavailable) responses the
+// server Retry-After header is honored
+type RetryPolicy struct {
+	MaxAttempts     int              // MaxAttempts maximum number of attempts for single request (including the first attempt)
+	InitialBackoff  time.Duration    // InitialBackoff delay before the first retry
+	MaxBackoff      time.Duration    // MaxBackoff maximum delay between retries
+	Multiplier      float64          // Multiplier applied to the delay after each retry
+	Jitter          float64          // Jitter fraction (0 to 1) of the delay that is randomized
+	MaxRetryAfter   time.Duration    // MaxRetryAfter longest Retry-After delay to wait for, longer delays are not retried (0 for no limit)
+	RetryableStatus []int            // RetryableStatus HTTP status codes that should be retried
+	RetryableError  func(error) bool // RetryableError report whether a transport error should be retried (default for timeouts and connection errors, except DNS not found and TLS errors)
+}
+
+// DefaultRetryPolicy return retry policy with 3 attempts, starting with 1 second backoff for network errors, 429 and 5xx
+// server errors
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     3,
+		InitialBackoff:  time.Second,
+		MaxBackoff:      30 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxRetryAfter:   2 * time.Minute,
+		RetryableStatus: []int{429, 500, 502, 503, 504},
+	}
+}
+
+// WithRetryPolicy set the crawler retry policy. By default failed requests are not retried
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Crawler) {
+		c.retryPolicy = p
+	}
+}
+
+// retryStatus return true if the HTTP status code should be retried
+func (p *RetryPolicy) retryStatus(code int) bool {
+	for _, s := range p.RetryableStatus {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryError return true if the transport error should be retried
+func (p *RetryPolicy) retryError(err error) bool {
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+
+	switch networkErrorKind(err) {
+	case ErrTLS:
+		// invalid certificate will not be fixed by retrying the request
+		return false
+	case ErrDNS:
+		// host not found will not be resolved by retrying the request
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+	}
+
+	// retry timeouts and connection level errors (i.e. connection refused or reset). Note that *url.Error returned by the
+	// HTTP client implements net.Error for any error, so only its Timeout method is checked
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// backoff return the delay before the next attempt, after the specified number of attempts
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	// randomize part of the delay to spread retries of concurrent requests
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// retryAfter parse the Retry-After response header, specified either as number of seconds or as HTTP date
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := res.Header.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sendRequestWithRetry send HTTP request to remote host, and retry it according to the crawler retry policy. It return the
+// last response (or error) and the number of attempts made
+func (c *Crawler) sendRequestWithRetry(ctx context.Context, req *Request, hop *Hop) (*http.Response, int, error) {
+	p := c.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.sendRequest(ctx, req, hop)
+		if p == nil || attempt >= p.MaxAttempts {
+			return res, attempt, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			// do not retry once the request is cancelled
+			if ctx.Err() != nil || !p.retryError(err) {
+				return nil, attempt, err
+			}
+			delay = p.backoff(attempt)
+			log.Printf("[%s] request to [%s] failed, retrying (attempt %d) in [%s]: %s", req.Domain, hop.URL, attempt+1, delay, err.Error())
+		case p.retryStatus(res.StatusCode):
+			delay = p.backoff(attempt)
+			if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+				if ra, ok := retryAfter(res); ok {
+					// the server asks to wait longer than we are willing to: return its response
+					if p.MaxRetryAfter > 0 && ra > p.MaxRetryAfter {
+						return res, attempt, nil
+					}
+					delay = ra
+				}
+			}
+			res.Body.Close()
+			log.Printf("[%s] request to [%s] failed with [%s], retrying (attempt %d) in [%s]", req.Domain, hop.URL, res.Status, attempt+1, delay)
+		default:
+			return res, attempt, nil
+		}
+
+		// wait for backoff delay, unless the request is cancelled
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, attempt, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retry policy with short backoff for tests
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 10 * time.Millisecond
+	return p
+}
+
+// TestRetryServerError test crawler retries request that failed with server error
+func TestRetryServerError(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRetryPolicy(testRetryPolicy()))
+
+	req, _ := NewRequest(ts.URL)
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Attempts != 3 {
+		t.Errorf("Expected number of attempts to be 3 and not [%d]", res.Attempts)
+	}
+
+	// without retry policy, the first server error fails the request
+	requests = 0
+	req, _ = NewRequest(ts.URL)
+	if _, err := NewCrawler().Get(req); err == nil {
+		t.Errorf("Expected request to fail without retry policy")
+	}
+}
+
+// TestRetryTLSError test crawler does not retry request that failed with TLS error, and retries connection errors
+func TestRetryTLSError(t *testing.T) {
+	var connections int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	// the test server certificate is not trusted by the crawler
+	req, _ := NewRequest(ts.URL)
+	_, err := NewCrawler(WithRetryPolicy(testRetryPolicy())).Get(req)
+	if !errors.Is(err, ErrTLS) {
+		t.Fatalf("Expected TLS error and not [%v]", err)
+	}
+	if n := atomic.LoadInt32(&connections); n != 1 {
+		t.Errorf("Expected TLS error to not be retried, but [%d] connections were made", n)
+	}
+
+	// connection refused is retried
+	u := ts.URL
+	ts.Close()
+	_, err = http.Get(u)
+	if err == nil || !testRetryPolicy().retryError(err) {
+		t.Errorf("Expected connection error to be retried [%v]", err)
+	}
+}
+
+// TestRetryAfter test crawler honors Retry-After header of 429 response
+func TestRetryAfter(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if requests == 2 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRetryPolicy(testRetryPolicy()))
+
+	// Retry-After longer than the policy MaxRetryAfter is not retried
+	req, _ := NewRequest(ts.URL)
+	if _, err := c.Get(req); err == nil {
+		t.Errorf("Expected request to fail when Retry-After is longer than max retry after")
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests to be sent and not [%d]", requests)
+	}
+}
+
+// TestRetryBackoff test retry delay grows exponentially up to max backoff
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		if d := p.backoff(i + 1); d != e {
+			t.Errorf("Expected backoff after attempt %d to be [%s] and not [%s]", i+1, e, d)
+		}
+	}
+
+	// jitter reduce the delay by up to the jitter fraction
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		if d := p.backoff(1); d < 500*time.Millisecond || d > time.Second {
+			t.Errorf("Expected backoff with jitter to be between [500ms] and [1s] and not [%s]", d)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package adstxt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRobotsTxtDisallowed is returned (wrapped in *Error) when a request is skipped because the remote host robots.txt file
+// disallow the crawler from fetching the requested URL
+var ErrRobotsTxtDisallowed = errors.New("disallowed by robots.txt")
+
+// robots.txt error
+const errRobotsTxtDisallowed = "[%s] robots.txt of [%s] does not allow crawling Ads.txt URL [%s]"
+
+// robots.txt crawler settings
+const (
+	robotsTxtTTL     = 24 * time.Hour   // robots.txt file is cached for up to 24 hours (RFC 9309 section 2.4)
+	robotsTxtMaxSize = 500 * 1024       // robots.txt is parsed up to 500 KiB (RFC 9309 section 2.5)
+	maxCrawlDelay    = 60 * time.Second // ignore unreasonable crawl delay
+)
+
+// WithRobotsTxt make the crawler fetch robots.txt file of each remote host (cached per host), skip requests disallowed for
+// the crawler user agent and wait for the robots.txt Crawl-delay between requests to the same host
+func WithRobotsTxt() Option {
+	return func(c *Crawler) {
+		c.robots = newRobotsCache()
+	}
+}
+
+// robotsRule single Allow/Disallow rule
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+// robotsGroup group of rules declared for one or more user agents
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsTxt parsed robots.txt file
+type robotsTxt struct {
+	groups []*robotsGroup
+}
+
+// parseRobotsTxt parse robots.txt file content
+func parseRobotsTxt(r io.Reader) *robotsTxt {
+	robots := &robotsTxt{}
+
+	var group *robotsGroup
+	inAgents := false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, robotsTxtMaxSize))
+	for scanner.Scan() {
+		line := removeComment(scanner.Text())
+		index := strings.Index(line, ":")
+		if index == -1 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:index]))
+		value := strings.TrimSpace(line[index+1:])
+
+		switch key {
+		case "user-agent":
+			// consecutive user-agent lines share the same group of rules
+			if !inAgents {
+				group = &robotsGroup{}
+				robots.groups = append(robots.groups, group)
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+			inAgents = true
+		case "allow", "disallow":
+			inAgents = false
+			// rules declared before any user-agent line are ignored, and empty disallow rule allow everything
+			if group == nil || len(value) == 0 {
+				continue
+			}
+			group.rules = append(group.rules, robotsRule{allow: key == "allow", path: value})
+		case "crawl-delay":
+			inAgents = false
+			if group == nil {
+				continue
+			}
+			if delay, err := strconv.ParseFloat(value, 64); err == nil && delay > 0 {
+				group.crawlDelay = time.Duration(delay * float64(time.Second))
+				if group.crawlDelay > maxCrawlDelay {
+					group.crawlDelay = maxCrawlDelay
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	return robots
+}
+
+// group return the group of rules that apply to the user agent: the group with the user agent product token (RFC 9309
+// section 2.2.1), or the "*" group if no specific group matches
+func (r *robotsTxt) group(userAgent string) *robotsGroup {
+	token := productToken(userAgent)
+
+	var wildcard *robotsGroup
+	for _, g := range r.groups {
+		for _, a := range g.agents {
+			if len(token) > 0 && a == token {
+				return g
+			}
+			if a == "*" && wildcard == nil {
+				wildcard = g
+			}
+		}
+	}
+
+	return wildcard
+}
+
+// productToken return the product token of the user agent (the text before "/" or space), in lower case
+func productToken(userAgent string) string {
+	ua := strings.TrimSpace(userAgent)
+	if i := strings.IndexAny(ua, "/ "); i != -1 {
+		ua = ua[:i]
+	}
+	return strings.ToLower(ua)
+}
+
+// allowed return true if the user agent is allowed to crawl the path. The most specific (longest) matching rule is used,
+// and Allow rule wins when Allow and Disallow rules are equally specific
+func (r *robotsTxt) allowed(userAgent string, path string) bool {
+	g := r.group(userAgent)
+	if g == nil {
+		return true
+	}
+
+	allow := true
+	matchLen := -1
+	for _, rule := range g.rules {
+		if !robotsPathMatch(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > matchLen || (len(rule.path) == matchLen && rule.allow) {
+			allow = rule.allow
+			matchLen = len(rule.path)
+		}
+	}
+
+	return allow
+}
+
+// crawlDelay return the Crawl-delay declared for the user agent
+func (r *robotsTxt) crawlDelay(userAgent string) time.Duration {
+	g := r.group(userAgent)
+	if g == nil {
+		return 0
+	}
+	return g.crawlDelay
+}
+
+// robotsPathMatch match URL path against robots.txt rule path, which may include "*" wildcard and "$" end of path marker
+func robotsPathMatch(pattern string, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	// first part must match the beginning of the path
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	if len(parts) == 1 {
+		return !anchored || len(rest) == 0
+	}
+
+	// match each wildcard separated part in order
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(rest, part)
+		if index == -1 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+
+	// last part of anchored pattern must match the end of the path
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// robotsEntry cached robots.txt of single host
+type robotsEntry struct {
+	once      sync.Once
+	robots    *robotsTxt
+	err       error     // err fetching robots.txt, entries of failed fetch are removed from the cache
+	created   time.Time // created time the entry was added to the cache
+	mu        sync.Mutex
+	nextFetch time.Time // nextFetch earliest time the next request can be sent to the host, according to crawl delay
+}
+
+// robotsCache cache of robots.txt files, keyed by host (scheme and authority)
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+// newRobotsCache create new empty robots.txt cache
+func newRobotsCache() *robotsCache {
+	return &robotsCache{entries: map[string]*robotsEntry{}}
+}
+
+// entry return the cached robots.txt of the host, fetching it if it is not cached yet or if it is expired. Failed fetch
+// is not cached, so the next request to the host fetch robots.txt again
+func (rc *robotsCache) entry(ctx context.Context, c *Crawler, u *url.URL) (*robotsEntry, error) {
+	host := u.Scheme + "://" + u.Host
+
+	for {
+		rc.mu.Lock()
+		e, ok := rc.entries[host]
+		if !ok || time.Since(e.created) > robotsTxtTTL {
+			e = &robotsEntry{created: time.Now()}
+			rc.entries[host] = e
+		}
+		rc.mu.Unlock()
+
+		e.once.Do(func() {
+			e.robots, e.err = c.fetchRobotsTxt(ctx, host)
+		})
+		if e.err == nil {
+			return e, nil
+		}
+
+		rc.mu.Lock()
+		if rc.entries[host] == e {
+			delete(rc.entries, host)
+		}
+		rc.mu.Unlock()
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// robots.txt was fetched by another request which context was cancelled, fetch it again
+		if errors.Is(e.err, context.Canceled) || errors.Is(e.err, context.DeadlineExceeded) {
+			continue
+		}
+		return e, nil
+	}
+}
+
+// wait until crawl delay since the previous request to the host has passed, unless the context is cancelled
+func (e *robotsEntry) wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	// reserve the next time slot for this request
+	e.mu.Lock()
+	now := time.Now()
+	slot := e.nextFetch
+	if slot.Before(now) {
+		slot = now
+	}
+	e.nextFetch = slot.Add(delay)
+	e.mu.Unlock()
+
+	t := time.NewTimer(time.Until(slot))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchRobotsTxt fetch and parse robots.txt file of the host. Following RFC 9309, unavailable robots.txt file (4xx status)
+// allow crawling, and unreachable robots.txt file (5xx status) disallow crawling. Network errors allow crawling, since the
+// same error is expected to be returned for the Ads.txt request itself. For network errors and unreachable robots.txt the
+// error is returned along with the robots.txt file used for the request, so the result is not cached
+func (c *Crawler) fetchRobotsTxt(ctx context.Context, host string) (*robotsTxt, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", host+"/robots.txt", nil)
+	if err != nil {
+		return &robotsTxt{}, err
+	}
+	httpRequest.Header.Add("User-Agent", c.UserAgent)
+
+	// robots.txt redirects are followed, unlike Ads.txt redirects that are handled by the crawler
+	client := *c.client
+	client.CheckRedirect = nil
+
+	res, err := client.Do(httpRequest)
+	if err != nil {
+		log.Printf("[%s] failed to fetch robots.txt [%s]", host, err.Error())
+		return &robotsTxt{}, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case 200 <= res.StatusCode && res.StatusCode < 300:
+		robots := parseRobotsTxt(res.Body)
+		// robots.txt body read was interrupted, the file may be incomplete
+		if err := ctx.Err(); err != nil {
+			return &robotsTxt{}, err
+		}
+		return robots, nil
+	case 500 <= res.StatusCode:
+		// unreachable robots.txt may be temporary, so it is not cached and is fetched again by the next request
+		unreachable := &robotsTxt{groups: []*robotsGroup{{agents: []string{"*"}, rules: []robotsRule{{allow: false, path: "/"}}}}}
+		return unreachable, fmt.Errorf("robots.txt is unreachable [%s]", res.Status)
+	default:
+		return &robotsTxt{}, nil
+	}
+}
+
+// checkRobotsTxt check the remote host robots.txt allow the crawler to fetch the request URL, and wait for the host crawl
+// delay before the request is sent
+func (c *Crawler) checkRobotsTxt(ctx context.Context, req *Request, rawurl string) error {
+	if c.robots == nil {
+		return nil
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.robots.entry(ctx, c, u)
+	if err != nil {
+		return err
+	}
+
+	path := u.EscapedPath()
+	if len(path) == 0 {
+		path = "/"
+	}
+	if len(u.RawQuery) > 0 {
+		path += "?" + u.RawQuery
+	}
+
+	if !e.robots.allowed(c.UserAgent, path) {
+		return newError(ErrRobotsTxtDisallowed, req, rawurl, nil, nil, errRobotsTxtDisallowed, req.Domain, u.Host, rawurl)
+	}
+
+	return e.wait(ctx, e.robots.crawlDelay(c.UserAgent))
+}
@@ -0,0 +1,217 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseRobotsTxt test parsing robots.txt rules for crawler user agent
+func TestParseRobotsTxt(t *testing.T) {
+	robots := parseRobotsTxt(strings.NewReader(`# robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/ads.txt
+Crawl-delay: 2
+
+User-agent: go-adstxt-crawler
+User-agent: other-bot
+Disallow: /*.txt$
+Allow: /app-ads.txt
+Crawl-delay: 0.5
+`))
+
+	paths := map[string]bool{
+		"/ads.txt":             false,
+		"/app-ads.txt":         true,
+		"/ads.txt?query":       true,
+		"/dir/ads.txt":         false,
+		"/private/index.html":  true,
+		"/path/sellers.json":   true,
+		"/private/ads.txt.bak": true,
+	}
+
+	for path, expected := range paths {
+		if robots.allowed(userAgent, path) != expected {
+			t.Errorf("Expected crawling [%s] to be allowed [%t]", path, expected)
+		}
+	}
+
+	// user agent not listed use the "*" group
+	if robots.allowed("some-bot", "/private/index.html") {
+		t.Errorf("Expected crawling [/private/index.html] to be disallowed for [some-bot]")
+	}
+	if !robots.allowed("some-bot", "/private/ads.txt") {
+		t.Errorf("Expected crawling [/private/ads.txt] to be allowed for [some-bot]")
+	}
+
+	// groups are matched by the user agent product token, and not by substring of the user agent
+	for _, ua := range []string{"Go-AdsTxt-Crawler/1.0", "other-bot (+https://example.com/bot)"} {
+		if robots.allowed(ua, "/ads.txt") {
+			t.Errorf("Expected crawling [/ads.txt] to be disallowed for [%s]", ua)
+		}
+	}
+	if !robots.allowed("go-adstxt-crawler-beta/1.0", "/ads.txt") || !robots.allowed("bot", "/ads.txt") {
+		t.Errorf("Expected user agent that is not the group product token to use the \"*\" group")
+	}
+	if !parseRobotsTxt(strings.NewReader("User-agent: a\nDisallow: /\n")).allowed(userAgent, "/ads.txt") {
+		t.Errorf("Expected group of substring of the user agent to not apply to [%s]", userAgent)
+	}
+
+	if d := robots.crawlDelay(userAgent); d != 500*time.Millisecond {
+		t.Errorf("Expected crawl delay to be [500ms] and not [%s]", d)
+	}
+	if d := robots.crawlDelay("some-bot"); d != 2*time.Second {
+		t.Errorf("Expected crawl delay to be [2s] and not [%s]", d)
+	}
+}
+
+// TestRobotsTxtDisallowed test crawler skip Ads.txt request disallowed by robots.txt
+func TestRobotsTxtDisallowed(t *testing.T) {
+	robotsRequests := 0
+	adsTxtRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			robotsRequests++
+			io.WriteString(w, "User-agent: *\nDisallow: /ads.txt\n")
+			return
+		}
+		adsTxtRequests++
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRobotsTxt())
+
+	for i := 0; i < 2; i++ {
+		req, _ := NewRequest(ts.URL)
+		_, err := c.Get(req)
+		if !errors.Is(err, ErrRobotsTxtDisallowed) {
+			t.Errorf("Expected request to be disallowed by robots.txt and not [%v]", err)
+		}
+	}
+
+	if robotsRequests != 1 {
+		t.Errorf("Expected robots.txt to be fetched once and not [%d] times", robotsRequests)
+	}
+
+	if adsTxtRequests != 0 {
+		t.Errorf("Expected Ads.txt request to be skipped")
+	}
+
+	// app-ads.txt is not disallowed
+	req, _ := NewAppRequest(ts.URL)
+	if _, err := c.Get(req); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestRobotsTxtCancelledFetch test robots.txt fetch cancelled by the request context is not cached as "allow all"
+func TestRobotsTxtCancelledFetch(t *testing.T) {
+	var robotsRequests int32
+	adsTxtRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			// first robots.txt request is slower than the request deadline
+			if atomic.AddInt32(&robotsRequests, 1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			io.WriteString(w, "User-agent: *\nDisallow: /\n")
+			return
+		}
+		adsTxtRequests++
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRobotsTxt())
+	req, _ := NewRequest(ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetContext(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error [%s] and not [%v]", context.DeadlineExceeded, err)
+	}
+
+	if _, err := c.GetContext(context.Background(), req); !errors.Is(err, ErrRobotsTxtDisallowed) {
+		t.Errorf("Expected request to be disallowed by robots.txt and not [%v]", err)
+	}
+	if n := atomic.LoadInt32(&robotsRequests); n != 2 {
+		t.Errorf("Expected robots.txt to be fetched again and not [%d] times", n)
+	}
+	if adsTxtRequests != 0 {
+		t.Errorf("Expected Ads.txt request to be skipped")
+	}
+}
+
+// TestRobotsTxtUnreachable test unreachable robots.txt (5xx status) disallow crawling, and is fetched again by the next
+// request
+func TestRobotsTxtUnreachable(t *testing.T) {
+	var robotsRequests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			if atomic.AddInt32(&robotsRequests, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			io.WriteString(w, "User-agent: *\nAllow: /\n")
+			return
+		}
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRobotsTxt())
+	req, _ := NewRequest(ts.URL)
+
+	if _, err := c.Get(req); !errors.Is(err, ErrRobotsTxtDisallowed) {
+		t.Errorf("Expected request to be disallowed by unreachable robots.txt and not [%v]", err)
+	}
+	if _, err := c.Get(req); err != nil {
+		t.Errorf("Expected request to be allowed once robots.txt is available and not [%v]", err)
+	}
+	if n := atomic.LoadInt32(&robotsRequests); n != 2 {
+		t.Errorf("Expected robots.txt to be fetched again and not [%d] times", n)
+	}
+}
+
+// TestRobotsTxtCrawlDelay test crawler wait for crawl delay between requests to the same host
+func TestRobotsTxtCrawlDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			io.WriteString(w, "User-agent: *\nCrawl-delay: 0.1\n")
+			return
+		}
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRobotsTxt())
+
+	requests := make([]*Request, 3)
+	for i := range requests {
+		requests[i], _ = NewRequest(ts.URL)
+	}
+
+	start := time.Now()
+	c.GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+
+	// first request is sent immediately, each following request wait for the crawl delay
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected crawling to take at least [200ms] and not [%s]", elapsed)
+	}
+}
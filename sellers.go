@@ -0,0 +1,264 @@
+package adstxt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sellers.json supported seller types
+const (
+	// SellerTypePublisher indicates that the inventory is owned by the seller (the seller is paid directly by the advertising system)
+	SellerTypePublisher = "PUBLISHER"
+	// SellerTypeIntermediary indicates that the seller is not the owner of the inventory
+	SellerTypeIntermediary = "INTERMEDIARY"
+	// SellerTypeBoth indicates that the seller is both a publisher and an intermediary
+	SellerTypeBoth = "BOTH"
+)
+
+// sellersJSONVersion current sellers.json specification version
+const sellersJSONVersion = "1.0"
+
+// Sellers holds collection of sellers parsed from sellers.json file, in addition to warnings found during sellers.json
+// file parsing. Warning index is the position (starting from 1) of the seller in the sellers list, or 0 for file level warnings
+type Sellers struct {
+	ContactEmail   string        `json:"contact_email,omitempty"`   // ContactEmail email address for inquiries about the sellers.json file
+	ContactAddress string        `json:"contact_address,omitempty"` // ContactAddress business address of the advertising system
+	Version        string        `json:"version"`                   // Version of the sellers.json specification the file follows
+	Identifiers    []*Identifier `json:"identifiers,omitempty"`     // Identifiers of the advertising system (i.e. TAG-ID)
+	Sellers        []*Seller     `json:"sellers"`                   // Sellers list of sellers authorized by the advertising system
+	Warnings       []*Warning    `json:"warnings"`
+}
+
+// Identifier hold single sellers.json business identifier of the advertising system
+type Identifier struct {
+	Name  string `json:"name"`  // Name of the identifier (i.e. TAG-ID, DUNS)
+	Value string `json:"value"` // Value of the identifier
+}
+
+// Seller hold single sellers.json seller record
+type Seller struct {
+	SellerID       string `json:"seller_id"`         // SellerID the identifier associated with the seller, matches Ads.txt publisher account ID (required)
+	SellerType     string `json:"seller_type"`       // SellerType enumeration of the type of account: PUBLISHER, INTERMEDIARY or BOTH (required)
+	Name           string `json:"name,omitempty"`    // Name of the company paid for inventory under the seller ID (required unless confidential)
+	Domain         string `json:"domain,omitempty"`  // Domain the business domain name of the seller (required unless confidential)
+	IsConfidential bool   `json:"is_confidential"`   // IsConfidential indicates whether the seller identity is confidential
+	IsPassthrough  bool   `json:"is_passthrough"`    // IsPassthrough indicates that the seller is an intermediary passing through the inventory
+	Comment        string `json:"comment,omitempty"` // Comment description of the seller
+}
+
+// SellersResponse to a sellers.json request: collection of sellers parsed from sellers.json file and file expiration date
+type SellersResponse struct {
+	*Request
+	*Sellers
+	Expires       time.Time     `json:"expires"`       // sellers.json file expiration date
+	ExpiresSource ExpiresSource `json:"expiresSource"` // ExpiresSource the source of the expiration date (caching header or default)
+	Attempts      int           `json:"attempts"`      // Attempts number of HTTP attempts made to fetch the file
+	FinalURL      string        `json:"finalUrl"`      // FinalURL the file was fetched from, after following redirects
+	Hops          []*Hop        `json:"hops"`          // Hops HTTP requests sent to fetch the file, the last one is the final URL
+	Header        http.Header   `json:"header"`        // Header of the final HTTP response
+	BodySize      int64         `json:"bodySize"`      // BodySize number of bytes read from the response body
+}
+
+// rawSellers sellers.json file structure before validation
+type rawSellers struct {
+	ContactEmail   string            `json:"contact_email"`
+	ContactAddress string            `json:"contact_address"`
+	Version        interface{}       `json:"version"`
+	Identifiers    []*Identifier     `json:"identifiers"`
+	Sellers        []json.RawMessage `json:"sellers"`
+}
+
+// GetSellers crawl and parse sellers.json file from advertising system domain based on IAB sellers.json Specification Version 1.0
+// https://iabtechlab.com/wp-content/uploads/2019/07/Sellers.json_Final.pdf
+func GetSellers(req *Request) (*SellersResponse, error) {
+	return defaultCrawler.GetSellersContext(context.Background(), req)
+}
+
+// GetSellersContext crawl and parse sellers.json file from advertising system domain, the provided context is used to cancel
+// the request or to set a deadline for it
+func GetSellersContext(ctx context.Context, req *Request) (*SellersResponse, error) {
+	return defaultCrawler.GetSellersContext(ctx, req)
+}
+
+// GetSellers crawl and parse sellers.json file from advertising system domain using the crawler settings
+func (c *Crawler) GetSellers(req *Request) (*SellersResponse, error) {
+	return c.GetSellersContext(context.Background(), req)
+}
+
+// GetSellersContext crawl and parse sellers.json file from advertising system domain using the crawler settings, the provided
+// context is used to cancel the request or to set a deadline for it
+func (c *Crawler) GetSellersContext(ctx context.Context, req *Request) (*SellersResponse, error) {
+	// send sellers.json request to remote server, follow redirects and read sellers.json file content
+	d, err := c.download(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sellers, err := ParseSellers(d.body)
+	if err != nil {
+		return nil, err
+	}
+	// fetch warnings are file level warnings (with no line index)
+	sellers.Warnings = append(sellers.Warnings, d.warnings...)
+
+	r := &SellersResponse{
+		Request:  req,
+		Sellers:  sellers,
+		Attempts: d.attempts,
+		FinalURL: d.finalURL(),
+		Hops:     d.hops,
+		Header:   d.res.Header,
+		BodySize: int64(len(d.body)),
+	}
+
+	// parse sellers.json expiration date from response caching headers (else the same default expiration as Ads.txt file is used)
+	r.Expires, r.ExpiresSource = c.expires(d.res)
+
+	return r, nil
+}
+
+// ParseSellers parse sellers.json file based on IAB sellers.json Specification Version 1.0
+// https://iabtechlab.com/wp-content/uploads/2019/07/Sellers.json_Final.pdf
+func ParseSellers(b []byte) (*Sellers, error) {
+	var raw rawSellers
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	s := &Sellers{
+		ContactEmail:   raw.ContactEmail,
+		ContactAddress: raw.ContactAddress,
+		Identifiers:    raw.Identifiers,
+		Sellers:        []*Seller{},
+		Warnings:       []*Warning{},
+	}
+
+	// version is required, and should be a string
+	switch v := raw.Version.(type) {
+	case string:
+		s.Version = v
+	case float64:
+		s.Version = fmt.Sprintf("%.1f", v)
+		s.Warnings = append(s.Warnings, &Warning{Level: LowSeverity, Message: fmt.Sprintf("version should be a string and not a number [%s]", s.Version)})
+	case nil:
+		s.Warnings = append(s.Warnings, &Warning{Level: HighSeverity, Message: "Missing sellers.json version (required)"})
+	default:
+		s.Warnings = append(s.Warnings, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%v] is not a valid sellers.json version", v)})
+	}
+	if len(s.Version) > 0 && s.Version != sellersJSONVersion {
+		s.Warnings = append(s.Warnings, &Warning{Level: LowSeverity, Message: fmt.Sprintf("sellers.json version [%s] is not supported, expected version [%s]",
+			s.Version, sellersJSONVersion)})
+	}
+
+	if raw.Sellers == nil {
+		s.Warnings = append(s.Warnings, &Warning{Level: HighSeverity, Message: "Missing sellers list (required)"})
+	}
+
+	// loop over sellers list and parse each seller record
+	for index, rs := range raw.Sellers {
+		seller, w := parseSeller(rs)
+		if w != nil {
+			w.Index = index + 1
+			w.Text = string(rs)
+			s.Warnings = append(s.Warnings, w)
+		}
+		if seller != nil {
+			s.Sellers = append(s.Sellers, seller)
+		}
+	}
+
+	return s, nil
+}
+
+// parseSeller return new Seller parsed from single sellers.json seller object
+func parseSeller(b []byte) (*Seller, *Warning) {
+	// numbers are decoded as json.Number, so large numeric seller IDs keep their literal text
+	var fields map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&fields); err != nil {
+		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("Seller record must be a JSON object [%s]", err.Error())}
+	}
+
+	// seller_id is required: the specification declares it as a string, but numeric IDs are common and unambiguous
+	var w *Warning
+	s := &Seller{}
+	switch id := fields["seller_id"].(type) {
+	case string:
+		s.SellerID = strings.TrimSpace(id)
+	case json.Number:
+		s.SellerID = id.String()
+		w = &Warning{Level: LowSeverity, Message: fmt.Sprintf("seller_id should be a string and not a number [%s]", s.SellerID)}
+	}
+	if len(s.SellerID) == 0 {
+		return nil, &Warning{Level: HighSeverity, Message: "Missing seller_id (required)"}
+	}
+
+	// make sure seller type is supported (case insensitive)
+	sellerType, _ := fields["seller_type"].(string)
+	s.SellerType = strings.ToUpper(strings.TrimSpace(sellerType))
+	if len(s.SellerType) == 0 {
+		return nil, &Warning{Level: HighSeverity, Message: "Missing seller_type (required)"}
+	}
+	if s.SellerType != SellerTypePublisher && s.SellerType != SellerTypeIntermediary && s.SellerType != SellerTypeBoth {
+		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid seller type. Seller type must be [%s], [%s] or [%s]",
+			sellerType, SellerTypePublisher, SellerTypeIntermediary, SellerTypeBoth)}
+	}
+
+	for _, flag := range []struct {
+		name  string
+		value *bool
+	}{{"is_confidential", &s.IsConfidential}, {"is_passthrough", &s.IsPassthrough}} {
+		var fw *Warning
+		*flag.value, fw = parseSellerFlag(flag.name, fields[flag.name])
+		if fw != nil && fw.Level == HighSeverity {
+			return s, fw
+		}
+		if w == nil {
+			w = fw
+		}
+	}
+
+	s.Name, _ = fields["name"].(string)
+	s.Domain, _ = fields["domain"].(string)
+	s.Comment, _ = fields["comment"].(string)
+	s.Domain = strings.TrimSpace(s.Domain)
+
+	// name and domain are required unless the seller is confidential
+	if !s.IsConfidential {
+		if len(strings.TrimSpace(s.Name)) == 0 {
+			return s, &Warning{Level: HighSeverity, Message: "Missing seller name (required unless seller is confidential)"}
+		}
+		if len(s.Domain) == 0 && s.SellerType != SellerTypeIntermediary {
+			return s, &Warning{Level: LowSeverity, Message: "Missing seller domain (required unless seller is confidential or has no web presence)"}
+		}
+	}
+
+	if len(s.Domain) > 0 && !validateDomainName(s.Domain) {
+		return s, &Warning{Level: LowSeverity, Message: fmt.Sprintf("%s is not a valid seller domain", s.Domain)}
+	}
+
+	return s, w
+}
+
+// parseSellerFlag parse sellers.json 0/1 flag value (missing value defaults to 0). Boolean value is accepted with low
+// severity warning, and any other value is invalid
+func parseSellerFlag(name string, v interface{}) (bool, *Warning) {
+	switch f := v.(type) {
+	case nil:
+		return false, nil
+	case json.Number:
+		if n, err := f.Float64(); err == nil && (n == 0 || n == 1) {
+			return n == 1, nil
+		}
+	case bool:
+		// boolean flag is not valid according to the specification, but its meaning is unambiguous
+		return f, &Warning{Level: LowSeverity, Message: fmt.Sprintf("%s should be 0 or 1 and not a boolean [%t]", name, f)}
+	}
+	return false, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%v] is not a valid %s value, value must be 0 or 1", v, name)}
+}
@@ -0,0 +1,174 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestParseSellers test parsing valid sellers.json file
+func TestParseSellers(t *testing.T) {
+	b := []byte(`{
+		"contact_email": "adops@greenadexchange.com",
+		"version": "1.0",
+		"identifiers": [{"name": "TAG-ID", "value": "28cb65e5bbc0bd5f"}],
+		"sellers": [
+			{"seller_id": "XF7342", "seller_type": "PUBLISHER", "name": "Example", "domain": "example.com"},
+			{"seller_id": "185", "seller_type": "intermediary", "name": "Reseller", "is_passthrough": 1},
+			{"seller_id": "XF7343", "seller_type": "BOTH", "is_confidential": 1}
+		]
+	}`)
+
+	s, err := ParseSellers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Warnings) > 0 {
+		t.Errorf("Expected no warnings when parsing sellers.json, but received [%d] warnings", len(s.Warnings))
+	}
+
+	if len(s.Sellers) != 3 {
+		t.Fatalf("Expected number of sellers to be 3 and not [%d]", len(s.Sellers))
+	}
+
+	if len(s.Identifiers) != 1 || s.Identifiers[0].Name != "TAG-ID" {
+		t.Errorf("Expected sellers.json TAG-ID identifier but received [%v]", s.Identifiers)
+	}
+
+	if s.Sellers[0].SellerID != "XF7342" || s.Sellers[0].SellerType != SellerTypePublisher || s.Sellers[0].Domain != "example.com" {
+		t.Errorf("Failed to parse seller [%v]", s.Sellers[0])
+	}
+
+	if s.Sellers[1].SellerType != SellerTypeIntermediary || !s.Sellers[1].IsPassthrough {
+		t.Errorf("Failed to parse seller [%v]", s.Sellers[1])
+	}
+
+	if !s.Sellers[2].IsConfidential {
+		t.Errorf("Expected seller [%s] to be confidential", s.Sellers[2].SellerID)
+	}
+}
+
+// TestParseSellersWarnings test parsing sellers.json file with schema violations
+func TestParseSellersWarnings(t *testing.T) {
+	b := []byte(`{
+		"sellers": [
+			{"seller_id": 7342, "seller_type": "PUBLISHER", "name": "Example", "domain": "example.com"},
+			{"seller_type": "PUBLISHER", "name": "Example"},
+			{"seller_id": "185", "seller_type": "unknown", "name": "Example"},
+			{"seller_id": "186", "seller_type": "PUBLISHER", "domain": "example.com"},
+			{"seller_id": "187", "seller_type": "PUBLISHER", "name": "Example", "is_confidential": 2}
+		]
+	}`)
+
+	s, err := ParseSellers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// expected warnings: missing version, and a warning for each seller
+	expected := []struct {
+		index int
+		level Severity
+	}{{0, HighSeverity}, {1, LowSeverity}, {2, HighSeverity}, {3, HighSeverity}, {4, HighSeverity}, {5, HighSeverity}}
+
+	if len(s.Warnings) != len(expected) {
+		t.Fatalf("Expected [%d] warnings but received [%d]", len(expected), len(s.Warnings))
+	}
+
+	for i, e := range expected {
+		if s.Warnings[i].Index != e.index || s.Warnings[i].Level != e.level {
+			t.Errorf("Expected warning #%d to be for seller [%d] with level [%d] but received [%v]", i, e.index, e.level, s.Warnings[i])
+		}
+	}
+
+	// sellers with missing ID or invalid type are ignored
+	if len(s.Sellers) != 3 {
+		t.Errorf("Expected number of sellers to be 3 and not [%d]", len(s.Sellers))
+	}
+
+	if s.Sellers[0].SellerID != "7342" {
+		t.Errorf("Expected numeric seller_id to be parsed as [7342] and not [%s]", s.Sellers[0].SellerID)
+	}
+}
+
+// TestParseSellersNumericID test numeric seller_id larger than float64 precision keep its literal value
+func TestParseSellersNumericID(t *testing.T) {
+	b := []byte(`{"version": "1.0", "sellers": [{"seller_id": 123456789012345678901, "seller_type": "INTERMEDIARY", "name": "Example"}]}`)
+
+	s, err := ParseSellers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Sellers) != 1 || s.Sellers[0].SellerID != "123456789012345678901" {
+		t.Errorf("Expected seller_id [123456789012345678901] and not [%v]", s.Sellers)
+	}
+	if len(s.Warnings) != 1 || s.Warnings[0].Level != LowSeverity {
+		t.Errorf("Expected numeric seller_id low severity warning and not [%v]", s.Warnings)
+	}
+}
+
+// TestParseSellersBooleanFlags test boolean is_confidential and is_passthrough flags are accepted with a warning
+func TestParseSellersBooleanFlags(t *testing.T) {
+	b := []byte(`{
+		"version": "1.0",
+		"sellers": [
+			{"seller_id": "XF7342", "seller_type": "PUBLISHER", "name": "Example", "domain": "example.com", "is_confidential": false},
+			{"seller_id": "XF7343", "seller_type": "INTERMEDIARY", "name": "Reseller", "is_passthrough": true, "comment": "passthrough"}
+		]
+	}`)
+
+	s, err := ParseSellers(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Sellers) != 2 || len(s.Warnings) != 2 {
+		t.Fatalf("Expected [2] sellers and [2] warnings and not [%d] and [%d]", len(s.Sellers), len(s.Warnings))
+	}
+	for _, w := range s.Warnings {
+		if w.Level != LowSeverity {
+			t.Errorf("Expected boolean flag warning to be low severity and not [%v]", w)
+		}
+	}
+
+	if s.Sellers[0].IsConfidential || s.Sellers[0].Name != "Example" || s.Sellers[0].Domain != "example.com" {
+		t.Errorf("Expected seller with boolean flag to be fully parsed and not [%+v]", s.Sellers[0])
+	}
+	if !s.Sellers[1].IsPassthrough || s.Sellers[1].Comment != "passthrough" {
+		t.Errorf("Expected passthrough seller to be fully parsed and not [%+v]", s.Sellers[1])
+	}
+}
+
+// TestGetSellers testing fetch and parse sellers.json file from remote host
+func TestGetSellers(t *testing.T) {
+	const expected = `{"version": "1.0", "sellers": [{"seller_id": "XF7342", "seller_type": "PUBLISHER", "name": "Example", "domain": "example.com"}]}`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sellers.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, expected)
+	}))
+	defer ts.Close()
+
+	// request mock
+	req, _ := NewSellersRequest(ts.URL)
+
+	res, err := GetSellers(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Request != req {
+		t.Errorf("Expected sellers.json response to include pointer to the request")
+	}
+
+	if len(res.Sellers.Sellers) != 1 {
+		t.Errorf("Expected single seller but found [%d]", len(res.Sellers.Sellers))
+	}
+}
@@ -0,0 +1,110 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations database schema changes, in order. The schema version (the number of migrations applied) is kept in SQLite
+// user_version pragma. New migrations should only be appended
+var migrations = []string{
+	// 1: crawl results and their records
+	`CREATE TABLE crawls (
+		id             INTEGER PRIMARY KEY AUTOINCREMENT,
+		domain         TEXT    NOT NULL COLLATE NOCASE,
+		url            TEXT    NOT NULL,
+		file_type      TEXT    NOT NULL,
+		crawled_at     INTEGER NOT NULL,
+		expires        INTEGER NOT NULL,
+		expires_source TEXT    NOT NULL,
+		final_url      TEXT    NOT NULL,
+		attempts       INTEGER NOT NULL,
+		body_size      INTEGER NOT NULL,
+		body           TEXT    NOT NULL,
+		metadata       TEXT    NOT NULL
+	);
+	CREATE INDEX crawls_domain ON crawls (domain, file_type, id);
+
+	CREATE TABLE data_records (
+		crawl_id          INTEGER NOT NULL REFERENCES crawls (id) ON DELETE CASCADE,
+		position          INTEGER NOT NULL,
+		ad_system         TEXT    NOT NULL COLLATE NOCASE,
+		account_id        TEXT    NOT NULL,
+		account_type      TEXT    NOT NULL,
+		cert_authority_id TEXT    NOT NULL,
+		PRIMARY KEY (crawl_id, position)
+	);
+	CREATE INDEX data_records_ad_system ON data_records (ad_system, account_id);
+	CREATE INDEX data_records_account_id ON data_records (account_id);
+
+	CREATE TABLE variables (
+		crawl_id     INTEGER NOT NULL REFERENCES crawls (id) ON DELETE CASCADE,
+		position     INTEGER NOT NULL,
+		type         TEXT    NOT NULL,
+		value        TEXT    NOT NULL,
+		country_code TEXT    NOT NULL,
+		PRIMARY KEY (crawl_id, position)
+	);
+
+	CREATE TABLE warnings (
+		crawl_id   INTEGER NOT NULL REFERENCES crawls (id) ON DELETE CASCADE,
+		position   INTEGER NOT NULL,
+		line_index INTEGER NOT NULL,
+		text       TEXT    NOT NULL,
+		message    TEXT    NOT NULL,
+		level      INTEGER NOT NULL,
+		PRIMARY KEY (crawl_id, position)
+	);`,
+	// 2: snapshots, each distinct file version of a domain and the interval it was seen. Existing crawls have no content
+	// hash, so each one is a snapshot of its own
+	`CREATE TABLE snapshots (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		crawl_id     INTEGER NOT NULL UNIQUE REFERENCES crawls (id) ON DELETE CASCADE,
+		domain       TEXT    NOT NULL COLLATE NOCASE,
+		file_type    TEXT    NOT NULL,
+		content_hash TEXT    NOT NULL,
+		first_seen   INTEGER NOT NULL,
+		last_seen    INTEGER NOT NULL
+	);
+	CREATE INDEX snapshots_domain ON snapshots (domain, file_type, first_seen);
+
+	INSERT INTO snapshots (crawl_id, domain, file_type, content_hash, first_seen, last_seen)
+		SELECT id, domain, file_type, '', crawled_at, crawled_at FROM crawls ORDER BY id;`,
+	// 3: warnings suggested fix, stored as JSON (empty if the warning has no fix)
+	`ALTER TABLE warnings ADD COLUMN fix TEXT NOT NULL DEFAULT '';`,
+	// 4: lines involved in file level warnings, stored as JSON (empty if the warning is of single line)
+	`ALTER TABLE warnings ADD COLUMN lines TEXT NOT NULL DEFAULT '';`,
+}
+
+// migrate apply the migrations that were not applied yet to the database, each in its own transaction
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version [%d] is newer than supported version [%d]", version, len(migrations))
+	}
+
+	for ; version < len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration [%d] [%s]", version+1, err.Error())
+		}
+		// pragma does not support parameters
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package sqlitestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// snapshot stored snapshot identifiers
+type snapshot struct {
+	id      int64
+	crawlID int64
+	hash    string
+}
+
+// contentHash return hash of the file parsed content (records, warnings and file body)
+func contentHash(rec *adstxt.Records) (string, error) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// lastSnapshot return the last snapshot of the domain file, or nil if the domain file has no snapshots
+func lastSnapshot(ctx context.Context, tx *sql.Tx, domain string, fileType adstxt.FileType) (*snapshot, error) {
+	last := &snapshot{}
+	err := tx.QueryRowContext(ctx, `SELECT id, crawl_id, content_hash FROM snapshots
+		WHERE domain = ? AND file_type = ? ORDER BY first_seen DESC, id DESC LIMIT 1`,
+		domain, string(fileType)).Scan(&last.id, &last.crawlID, &last.hash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// AsOf return the snapshot of the domain file in effect at time t (the last version first seen at or before t), or
+// adstxt.ErrNotStored
+func (s *Store) AsOf(ctx context.Context, domain string, fileType adstxt.FileType, t time.Time) (*adstxt.Snapshot, error) {
+	sn := &adstxt.Snapshot{}
+	var crawlID, firstSeen, lastSeen int64
+	var ft string
+	err := s.db.QueryRowContext(ctx, `SELECT crawl_id, domain, file_type, content_hash, first_seen, last_seen FROM snapshots
+		WHERE domain = ? AND file_type = ? AND first_seen <= ? ORDER BY first_seen DESC, id DESC LIMIT 1`,
+		domain, string(fileType), t.UnixNano()).Scan(&crawlID, &sn.Domain, &ft, &sn.Hash, &firstSeen, &lastSeen)
+	if err == sql.ErrNoRows {
+		return nil, adstxt.ErrNotStored
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.load(ctx, crawlID)
+	if err != nil {
+		return nil, err
+	}
+
+	sn.Records = r.Records
+	sn.FileType = adstxt.FileType(ft)
+	sn.FirstSeen = time.Unix(0, firstSeen).UTC()
+	sn.LastSeen = time.Unix(0, lastSeen).UTC()
+	return sn, nil
+}
+
+// Snapshots return the domain file snapshots ordered by first seen time, without their records
+func (s *Store) Snapshots(ctx context.Context, domain string, fileType adstxt.FileType) ([]*adstxt.Snapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT domain, file_type, content_hash, first_seen, last_seen FROM snapshots
+		WHERE domain = ? AND file_type = ? ORDER BY first_seen, id`, domain, string(fileType))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []*adstxt.Snapshot{}
+	for rows.Next() {
+		sn := &adstxt.Snapshot{}
+		var ft string
+		var firstSeen, lastSeen int64
+		if err := rows.Scan(&sn.Domain, &ft, &sn.Hash, &firstSeen, &lastSeen); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sn.FileType = adstxt.FileType(ft)
+		sn.FirstSeen = time.Unix(0, firstSeen).UTC()
+		sn.LastSeen = time.Unix(0, lastSeen).UTC()
+		snapshots = append(snapshots, sn)
+	}
+
+	return snapshots, closeRows(rows)
+}
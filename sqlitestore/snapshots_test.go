@@ -0,0 +1,96 @@
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestSnapshots test unchanged crawl results are deduplicated and each file version is kept as snapshot
+func TestSnapshots(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	bodies := []string{
+		"google.com, XF7342, DIRECT",
+		"google.com, XF7342, DIRECT",
+		"google.com, XF7342, DIRECT\nappnexus.com, 1234, RESELLER",
+		"google.com, XF7342, DIRECT",
+	}
+	// crawl example.com once a day
+	for i, body := range bodies {
+		s.now = func() time.Time { return start.AddDate(0, 0, i) }
+		if err := s.Save(ctx, newTestResponse(t, "example.com", body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := s.Snapshots(ctx, "example.com", adstxt.AdsTxt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected [3] snapshots and not [%d]", len(snapshots))
+	}
+	if !snapshots[0].FirstSeen.Equal(start) || !snapshots[0].LastSeen.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("Expected first snapshot to be seen from [%s] to [%s] and not from [%s] to [%s]",
+			start, start.AddDate(0, 0, 1), snapshots[0].FirstSeen, snapshots[0].LastSeen)
+	}
+	if snapshots[0].Hash != snapshots[2].Hash || snapshots[0].Hash == snapshots[1].Hash {
+		t.Errorf("Expected snapshots hash to match their content")
+	}
+
+	var crawls int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM crawls").Scan(&crawls); err != nil {
+		t.Fatal(err)
+	}
+	if crawls != 3 {
+		t.Errorf("Expected [3] saved crawl results and not [%d]", crawls)
+	}
+}
+
+// TestAsOf test loading the snapshot in effect at a given time
+func TestAsOf(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	bodies := []string{
+		"google.com, XF7342, DIRECT",
+		"google.com, XF7342, DIRECT\nappnexus.com, 1234, RESELLER",
+	}
+	for i, body := range bodies {
+		s.now = func() time.Time { return start.AddDate(0, 0, 2*i) }
+		if err := s.Save(ctx, newTestResponse(t, "example.com", body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		t        time.Time
+		expected int
+	}{
+		{start, 1},
+		{start.AddDate(0, 0, 1), 1},
+		{start.AddDate(0, 0, 2), 2},
+		{start.AddDate(1, 0, 0), 2},
+	}
+
+	for _, test := range tests {
+		sn, err := s.AsOf(ctx, "Example.com", adstxt.AdsTxt, test.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sn.DataRecords) != test.expected {
+			t.Errorf("Expected [%d] data records as of [%s] and not [%d]", test.expected, test.t, len(sn.DataRecords))
+		}
+	}
+
+	if _, err := s.AsOf(ctx, "example.com", adstxt.AdsTxt, start.Add(-time.Second)); !errors.Is(err, adstxt.ErrNotStored) {
+		t.Errorf("Expected error [%s] and not [%v]", adstxt.ErrNotStored, err)
+	}
+}
@@ -0,0 +1,397 @@
+// Package sqlitestore implements adstxt.Store backed by SQLite database, using pure Go SQLite driver (no cgo required)
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+
+	// register pure Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+// Store adstxt.SnapshotStore implementation backed by SQLite database. Store is safe for concurrent use
+type Store struct {
+	db  *sql.DB
+	now func() time.Time // now return the current time, replaced in tests
+}
+
+// metadata crawl result fetch metadata, stored as JSON
+type metadata struct {
+	Variant     string                    `json:"variant"`
+	Fallbacks   []*adstxt.FallbackAttempt `json:"fallbacks"`
+	Hops        []*adstxt.Hop             `json:"hops"`
+	Header      http.Header               `json:"header"`
+	CacheStatus adstxt.CacheStatus        `json:"cacheStatus"`
+}
+
+// Open open (or create) SQLite database file and migrate it to the latest schema
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows single writer, so a single connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New create Store using an open SQLite database, and migrate it to the latest schema
+func New(db *sql.DB) (*Store, error) {
+	if err := migrate(context.Background(), db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save crawl result. When the file content has not changed since the last saved crawl result of the domain file, the
+// last crawl result fetch details are replaced and its snapshot last seen time is extended, instead of saving new
+// snapshot
+func (s *Store) Save(ctx context.Context, r *adstxt.Response) error {
+	hash, err := contentHash(r.Records)
+	if err != nil {
+		return err
+	}
+	crawledAt := s.now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	last, err := lastSnapshot(ctx, tx, r.Domain, fileType(r.Request))
+	if err != nil {
+		return err
+	}
+
+	if last != nil && last.hash == hash {
+		if err := updateCrawl(ctx, tx, last.crawlID, r, crawledAt); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE snapshots SET last_seen = ? WHERE id = ?", crawledAt.UnixNano(), last.id); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	id, err := saveCrawl(ctx, tx, r, crawledAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO snapshots (crawl_id, domain, file_type, content_hash, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?)`, id, strings.ToLower(r.Domain), string(fileType(r.Request)), hash,
+		crawledAt.UnixNano(), crawledAt.UnixNano())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// marshalMetadata return the crawl result fetch metadata as JSON
+func marshalMetadata(r *adstxt.Response) (string, error) {
+	meta, err := json.Marshal(&metadata{
+		Variant:     r.Variant,
+		Fallbacks:   r.Fallbacks,
+		Hops:        r.Hops,
+		Header:      r.Header,
+		CacheStatus: r.CacheStatus,
+	})
+	return string(meta), err
+}
+
+// updateCrawl replace the fetch details of saved crawl result with those of unchanged crawl result r
+func updateCrawl(ctx context.Context, tx *sql.Tx, id int64, r *adstxt.Response, crawledAt time.Time) error {
+	meta, err := marshalMetadata(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE crawls
+		SET url = ?, crawled_at = ?, expires = ?, expires_source = ?, final_url = ?, attempts = ?, body_size = ?, metadata = ?
+		WHERE id = ?`,
+		r.URL, crawledAt.UnixNano(), r.Expires.UnixNano(), string(r.ExpiresSource), r.FinalURL, r.Attempts, r.BodySize, meta, id)
+	return err
+}
+
+// saveCrawl insert crawl result and its records, and return the new crawl ID
+func saveCrawl(ctx context.Context, tx *sql.Tx, r *adstxt.Response, crawledAt time.Time) (int64, error) {
+	body, err := json.Marshal(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	meta, err := marshalMetadata(r)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO crawls
+		(domain, url, file_type, crawled_at, expires, expires_source, final_url, attempts, body_size, body, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		strings.ToLower(r.Domain), r.URL, string(fileType(r.Request)), crawledAt.UnixNano(), r.Expires.UnixNano(),
+		string(r.ExpiresSource), r.FinalURL, r.Attempts, r.BodySize, string(body), meta)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, dr := range r.DataRecords {
+		_, err := tx.ExecContext(ctx, `INSERT INTO data_records
+			(crawl_id, position, ad_system, account_id, account_type, cert_authority_id) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, i, dr.AdverterDomain, dr.PublisherAccountID, dr.AccountType, dr.CertAuthorityID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for i, v := range r.Variables {
+		_, err := tx.ExecContext(ctx, `INSERT INTO variables
+			(crawl_id, position, type, value, country_code) VALUES (?, ?, ?, ?, ?)`,
+			id, i, v.Type, v.Value, v.CountryCode)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for i, w := range r.Warnings {
+		fix, lines := "", ""
+		if w.Fix != nil {
+			b, err := json.Marshal(w.Fix)
+			if err != nil {
+				return 0, err
+			}
+			fix = string(b)
+		}
+		if len(w.Lines) > 0 {
+			b, err := json.Marshal(w.Lines)
+			if err != nil {
+				return 0, err
+			}
+			lines = string(b)
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO warnings
+			(crawl_id, position, line_index, text, message, level, fix, lines) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, i, w.Index, w.Text, w.Message, int(w.Level), fix, lines)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+// Latest return the last crawl result saved for the domain file, or adstxt.ErrNotStored
+func (s *Store) Latest(ctx context.Context, domain string, fileType adstxt.FileType) (*adstxt.Response, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM crawls WHERE domain = ? AND file_type = ? ORDER BY id DESC LIMIT 1",
+		domain, string(fileType)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, adstxt.ErrNotStored
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, id)
+}
+
+// load crawl result by its ID
+func (s *Store) load(ctx context.Context, id int64) (*adstxt.Response, error) {
+	r := &adstxt.Response{
+		Request: &adstxt.Request{},
+		Records: &adstxt.Records{
+			DataRecords: []*adstxt.DataRecord{},
+			Variables:   []*adstxt.Variable{},
+			Warnings:    []*adstxt.Warning{},
+		},
+	}
+
+	var fileType, expiresSource, body, meta string
+	var expires int64
+	err := s.db.QueryRowContext(ctx, `SELECT domain, url, file_type, expires, expires_source, final_url, attempts, body_size, body, metadata
+		FROM crawls WHERE id = ?`, id).Scan(&r.Domain, &r.URL, &fileType, &expires, &expiresSource, &r.FinalURL, &r.Attempts,
+		&r.BodySize, &body, &meta)
+	if err == sql.ErrNoRows {
+		return nil, adstxt.ErrNotStored
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.FileType = adstxt.FileType(fileType)
+	r.Expires = time.Unix(0, expires).UTC()
+	r.ExpiresSource = adstxt.ExpiresSource(expiresSource)
+	if err := json.Unmarshal([]byte(body), &r.Body); err != nil {
+		return nil, err
+	}
+	m := &metadata{}
+	if err := json.Unmarshal([]byte(meta), m); err != nil {
+		return nil, err
+	}
+	r.Variant, r.Fallbacks, r.Hops, r.Header, r.CacheStatus = m.Variant, m.Fallbacks, m.Hops, m.Header, m.CacheStatus
+
+	if err := s.loadRecords(ctx, id, r.Records); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// loadRecords load the data records, variables and warnings of crawl result
+func (s *Store) loadRecords(ctx context.Context, id int64, rec *adstxt.Records) error {
+	rows, err := s.db.QueryContext(ctx, `SELECT ad_system, account_id, account_type, cert_authority_id
+		FROM data_records WHERE crawl_id = ? ORDER BY position`, id)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		dr := &adstxt.DataRecord{}
+		if err := rows.Scan(&dr.AdverterDomain, &dr.PublisherAccountID, &dr.AccountType, &dr.CertAuthorityID); err != nil {
+			rows.Close()
+			return err
+		}
+		rec.DataRecords = append(rec.DataRecords, dr)
+	}
+	if err := closeRows(rows); err != nil {
+		return err
+	}
+
+	rows, err = s.db.QueryContext(ctx, "SELECT type, value, country_code FROM variables WHERE crawl_id = ? ORDER BY position", id)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		v := &adstxt.Variable{}
+		if err := rows.Scan(&v.Type, &v.Value, &v.CountryCode); err != nil {
+			rows.Close()
+			return err
+		}
+		rec.Variables = append(rec.Variables, v)
+	}
+	if err := closeRows(rows); err != nil {
+		return err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT line_index, text, message, level, fix, lines
+		FROM warnings WHERE crawl_id = ? ORDER BY position`, id)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		w := &adstxt.Warning{}
+		var fix, lines string
+		if err := rows.Scan(&w.Index, &w.Text, &w.Message, &w.Level, &fix, &lines); err != nil {
+			rows.Close()
+			return err
+		}
+		if len(fix) > 0 {
+			w.Fix = &adstxt.Fix{}
+			if err := json.Unmarshal([]byte(fix), w.Fix); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		if len(lines) > 0 {
+			if err := json.Unmarshal([]byte(lines), &w.Lines); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rec.Warnings = append(rec.Warnings, w)
+	}
+	return closeRows(rows)
+}
+
+// Query return the data records of the last crawl result of each domain file that match the query
+func (s *Store) Query(ctx context.Context, q *adstxt.StoreQuery) ([]*adstxt.StoredRecord, error) {
+	where, args := queryFilter(q)
+	return s.queryRecords(ctx, `SELECT c.id, c.domain, c.file_type, c.crawled_at,
+		r.ad_system, r.account_id, r.account_type, r.cert_authority_id
+		FROM data_records r JOIN crawls c ON c.id = r.crawl_id
+		WHERE c.id IN (SELECT MAX(id) FROM crawls GROUP BY domain, file_type)`+where+`
+		ORDER BY c.domain, c.file_type, r.position`, args...)
+}
+
+// queryFilter return SQL conditions and their arguments for the query non-empty fields
+func queryFilter(q *adstxt.StoreQuery) (string, []interface{}) {
+	where := ""
+	args := []interface{}{}
+	if len(q.Domain) > 0 {
+		where += " AND c.domain = ?"
+		args = append(args, q.Domain)
+	}
+	if len(q.FileType) > 0 {
+		where += " AND c.file_type = ?"
+		args = append(args, string(q.FileType))
+	}
+	if len(q.AdSystem) > 0 {
+		where += " AND r.ad_system = ?"
+		args = append(args, q.AdSystem)
+	}
+	if len(q.AccountID) > 0 {
+		where += " AND r.account_id = ?"
+		args = append(args, q.AccountID)
+	}
+	return where, args
+}
+
+// queryRecords run query that select crawl (id, domain, file type, crawled at) and data record columns
+func (s *Store) queryRecords(ctx context.Context, query string, args ...interface{}) ([]*adstxt.StoredRecord, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*adstxt.StoredRecord{}
+	for rows.Next() {
+		sr := &adstxt.StoredRecord{DataRecord: &adstxt.DataRecord{}}
+		var fileType string
+		var crawledAt int64
+		err := rows.Scan(&sr.CrawlID, &sr.Domain, &fileType, &crawledAt,
+			&sr.AdverterDomain, &sr.PublisherAccountID, &sr.AccountType, &sr.CertAuthorityID)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sr.FileType = adstxt.FileType(fileType)
+		sr.CrawledAt = time.Unix(0, crawledAt).UTC()
+		records = append(records, sr)
+	}
+
+	return records, closeRows(rows)
+}
+
+// closeRows close rows and return the error encountered during iteration, if any
+func closeRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// fileType return the request file type, empty file type is Ads.txt file
+func fileType(req *adstxt.Request) adstxt.FileType {
+	if req == nil || len(req.FileType) == 0 {
+		return adstxt.AdsTxt
+	}
+	return req.FileType
+}
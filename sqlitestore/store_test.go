@@ -0,0 +1,173 @@
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// newTestStore create new Store in temporary database file
+func newTestStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "adstxt.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// newTestResponse create new crawl result for domain with Ads.txt file content
+func newTestResponse(t *testing.T, domain string, body string) *adstxt.Response {
+	req, err := adstxt.NewRequest(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := adstxt.ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &adstxt.Response{
+		Request:       req,
+		Records:       rec,
+		Expires:       time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+		ExpiresSource: adstxt.ExpiresMaxAge,
+		Attempts:      1,
+		FinalURL:      req.URL,
+		Hops:          []*adstxt.Hop{{URL: req.URL, StatusCode: http.StatusOK}},
+		Header:        http.Header{"Etag": {`"v1"`}},
+		BodySize:      int64(len(body)),
+	}
+}
+
+// TestMigrate test migrations are applied once
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adstxt.db")
+
+	for i := 0; i < 2; i++ {
+		s, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var version int
+		if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+		if version != len(migrations) {
+			t.Errorf("Expected schema version [%d] and not [%d]", len(migrations), version)
+		}
+		s.Close()
+	}
+}
+
+// TestSaveLatest test saving crawl results and loading the latest one
+func TestSaveLatest(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.Latest(ctx, "example.com", adstxt.AdsTxt); !errors.Is(err, adstxt.ErrNotStored) {
+		t.Errorf("Expected error [%s] and not [%v]", adstxt.ErrNotStored, err)
+	}
+
+	if err := s.Save(ctx, newTestResponse(t, "example.com", "google.com, XF7342, DIRECT")); err != nil {
+		t.Fatal(err)
+	}
+	expected := newTestResponse(t, "example.com", "google.com, XF7342, DIRECT\nOWNERDOMAIN=example.com\ninvalid line")
+	if err := s.Save(ctx, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.Latest(ctx, "example.com", adstxt.AdsTxt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Domain != "example.com" || r.URL != expected.URL || r.FileType != adstxt.AdsTxt {
+		t.Errorf("Expected request [%+v] and not [%+v]", expected.Request, r.Request)
+	}
+	if len(r.DataRecords) != 1 || len(r.Variables) != 1 || len(r.Warnings) != 1 || len(r.Body) != 3 {
+		t.Errorf("Expected [1] data record, [1] variable, [1] warning and [3] lines and not [%d], [%d], [%d], [%d]",
+			len(r.DataRecords), len(r.Variables), len(r.Warnings), len(r.Body))
+	}
+	if *r.DataRecords[0] != *expected.DataRecords[0] || !reflect.DeepEqual(r.Warnings[0], expected.Warnings[0]) {
+		t.Errorf("Expected records to be loaded as saved")
+	}
+	if !r.Expires.Equal(expected.Expires) || r.ExpiresSource != expected.ExpiresSource {
+		t.Errorf("Expected expires [%s] (%s) and not [%s] (%s)", expected.Expires, expected.ExpiresSource, r.Expires, r.ExpiresSource)
+	}
+	if r.Header.Get("ETag") != `"v1"` || len(r.Hops) != 1 || r.Hops[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected fetch metadata to be loaded as saved")
+	}
+}
+
+// TestQuery test querying the records of the latest crawl of each domain
+func TestQuery(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	responses := []*adstxt.Response{
+		newTestResponse(t, "example.com", "google.com, XF7342, DIRECT\nappnexus.com, 1234, RESELLER"),
+		// latest crawl of example.com replace the first one
+		newTestResponse(t, "example.com", "google.com, XF7342, DIRECT"),
+		newTestResponse(t, "example.net", "Google.com, XF7342, RESELLER\nappnexus.com, 1234, DIRECT"),
+	}
+	for _, r := range responses {
+		if err := s.Save(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		q        *adstxt.StoreQuery
+		expected int
+	}{
+		{&adstxt.StoreQuery{}, 3},
+		{&adstxt.StoreQuery{Domain: "example.com"}, 1},
+		{&adstxt.StoreQuery{AdSystem: "google.com"}, 2},
+		{&adstxt.StoreQuery{AdSystem: "appnexus.com", AccountID: "1234"}, 1},
+		{&adstxt.StoreQuery{AccountID: "XF7342", FileType: adstxt.AppAdsTxt}, 0},
+	}
+
+	for _, test := range tests {
+		records, err := s.Query(ctx, test.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != test.expected {
+			t.Errorf("Expected query [%+v] to return [%d] records and not [%d]", test.q, test.expected, len(records))
+		}
+		for _, r := range records {
+			if r.CrawlID == 0 || r.CrawledAt.IsZero() || len(r.Domain) == 0 {
+				t.Errorf("Expected stored record crawl details and not [%+v]", r)
+			}
+		}
+	}
+}
+
+// TestSaveWarningFix test warnings suggested fix is saved and loaded
+func TestSaveWarningFix(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	expected := newTestResponse(t, "example.com", "https://google.com, XF7342, DIRECT")
+	if len(expected.Warnings) != 1 || expected.Warnings[0].Fix == nil {
+		t.Fatalf("Expected warning with suggested fix and not [%v]", expected.Warnings)
+	}
+	if err := s.Save(ctx, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.Latest(ctx, "example.com", adstxt.AdsTxt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Warnings) != 1 || r.Warnings[0].Fix == nil || *r.Warnings[0].Fix != *expected.Warnings[0].Fix {
+		t.Errorf("Expected warning fix [%+v] to be loaded as saved and not [%v]", expected.Warnings[0].Fix, r.Warnings)
+	}
+}
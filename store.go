@@ -0,0 +1,75 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrNotStored is returned by Store when no crawl result is stored for the requested domain
+var ErrNotStored = errors.New("not stored")
+
+// Store saves crawl results (request, records, warnings, expiration and fetch metadata) so they can be loaded and
+// queried later. See sqlitestore package for SQLite implementation
+type Store interface {
+	// Save crawl result
+	Save(ctx context.Context, r *Response) error
+	// Latest return the last crawl result saved for the domain file, or ErrNotStored
+	Latest(ctx context.Context, domain string, fileType FileType) (*Response, error)
+	// Query return the data records of the last crawl result of each domain file that match the query
+	Query(ctx context.Context, q *StoreQuery) ([]*StoredRecord, error)
+}
+
+// SnapshotStore Store that keeps the history of each domain file: unchanged crawl results are deduplicated by their
+// content, and each distinct file version is kept as Snapshot with the interval it was seen by the crawler
+type SnapshotStore interface {
+	Store
+	// AsOf return the snapshot of the domain file in effect at time t (the last version first seen at or before t), or
+	// ErrNotStored
+	AsOf(ctx context.Context, domain string, fileType FileType, t time.Time) (*Snapshot, error)
+	// Snapshots return the domain file snapshots ordered by first seen time, without their records
+	Snapshots(ctx context.Context, domain string, fileType FileType) ([]*Snapshot, error)
+}
+
+// Snapshot distinct version of a domain file, and the interval it was seen by the crawler. Snapshot is in effect from
+// its first seen time until the next snapshot first seen time
+type Snapshot struct {
+	*Records
+	Domain    string    `json:"domain"`    // Domain the file was crawled from
+	FileType  FileType  `json:"filetype"`  // FileType of the file (ads.txt or app-ads.txt)
+	Hash      string    `json:"hash"`      // Hash of the file parsed content
+	FirstSeen time.Time `json:"firstSeen"` // FirstSeen time the file version was first crawled
+	LastSeen  time.Time `json:"lastSeen"`  // LastSeen time the file version was last crawled
+}
+
+// StoreQuery filter data records stored in Store. Empty fields match all records
+type StoreQuery struct {
+	Domain    string   // Domain the record was crawled from
+	FileType  FileType // FileType the record was crawled from (ads.txt or app-ads.txt)
+	AdSystem  string   // AdSystem domain name of the advertising system (case insensitive)
+	AccountID string   // AccountID publisher account ID in the advertising system
+}
+
+// StoredRecord data record returned by Store query, with the crawl it was parsed from
+type StoredRecord struct {
+	*DataRecord
+	Domain    string    `json:"domain"`    // Domain the record was crawled from
+	FileType  FileType  `json:"filetype"`  // FileType the record was crawled from
+	CrawlID   int64     `json:"crawlId"`   // CrawlID identifier of the crawl result in the store
+	CrawledAt time.Time `json:"crawledAt"` // CrawledAt time the crawl result was saved
+}
+
+// StoreHandler return Handler that save each successful crawl result to the store, and then call the handler h (if not nil)
+func StoreHandler(s Store, h Handler) Handler {
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		if err == nil && res != nil {
+			if serr := s.Save(context.Background(), res); serr != nil {
+				log.Printf("[%s] failed to save crawl result [%s]", req.Domain, serr.Error())
+			}
+		}
+		if h != nil {
+			h.Handle(req, res, err)
+		}
+	})
+}
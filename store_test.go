@@ -0,0 +1,45 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memoryStore Store that keeps saved responses in memory
+type memoryStore struct {
+	saved []*Response
+}
+
+func (s *memoryStore) Save(ctx context.Context, r *Response) error {
+	s.saved = append(s.saved, r)
+	return nil
+}
+
+func (s *memoryStore) Latest(ctx context.Context, domain string, fileType FileType) (*Response, error) {
+	return nil, ErrNotStored
+}
+
+func (s *memoryStore) Query(ctx context.Context, q *StoreQuery) ([]*StoredRecord, error) {
+	return nil, nil
+}
+
+// TestStoreHandler test store handler save successful crawl results and call the next handler
+func TestStoreHandler(t *testing.T) {
+	s := &memoryStore{}
+	handled := 0
+	h := StoreHandler(s, HandlerFunc(func(req *Request, res *Response, err error) {
+		handled++
+	}))
+
+	req, _ := NewRequest("example.com")
+	h.Handle(req, &Response{Request: req}, nil)
+	h.Handle(req, nil, errors.New("failed"))
+
+	if len(s.saved) != 1 {
+		t.Errorf("Expected [1] saved response and not [%d]", len(s.saved))
+	}
+	if handled != 2 {
+		t.Errorf("Expected [2] handled responses and not [%d]", handled)
+	}
+}
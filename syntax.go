@@ -0,0 +1,178 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// File lossless syntax tree of Ads.txt file: each line of the file is kept with its whitespace, comment and line ending,
+// so the file can be edited and written back byte-identical where it was not modified
+type File struct {
+	BOM   bool        // BOM file starts with UTF-8 byte order mark
+	Lines []*LineNode // Lines of the file, in order
+}
+
+// LineNode single line of Ads.txt file syntax tree. The line text is Leading + Content + Trailing + Comment, followed by
+// the line ending EOL
+type LineNode struct {
+	Leading  string // Leading whitespace before the line content
+	Content  string // Content of the line: data record or variable declaration, without whitespace and comment
+	Trailing string // Trailing whitespace after the content
+	Comment  string // Comment of the line, starting with "#" (empty if the line has no comment)
+	EOL      string // EOL line ending ("\n", "\r\n" or "\r"), empty for the last line if the file does not end with newline
+
+	DataRecord *DataRecord // DataRecord parsed from the line content, if any
+	Variable   *Variable   // Variable parsed from the line content, if any
+	Warning    *Warning    // Warning found parsing the line, if any
+}
+
+// ParseFile parse Ads.txt file into lossless syntax tree. Each line is also parsed into Data\Variable record, the same
+// way ParseBody parse it
+func ParseFile(b []byte) *File {
+	f := &File{Lines: []*LineNode{}}
+	if bytes.HasPrefix(b, utf8BOM) {
+		f.BOM = true
+		b = b[len(utf8BOM):]
+	}
+
+	rec := newRecords()
+	s := string(b)
+	for index := 1; len(s) > 0; index++ {
+		text, eol := s, ""
+		if i := strings.IndexAny(s, "\r\n"); i != -1 {
+			text, eol = s[:i], s[i:i+1]
+			if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+				eol = "\r\n"
+			}
+		}
+		s = s[len(text)+len(eol):]
+
+		n := newLineNode(text)
+		n.EOL = eol
+
+		txt, _ := decodeLine(text)
+		l := rec.parseLine(index, txt)
+		rec.add(l)
+		n.DataRecord, n.Variable, n.Warning = l.DataRecord, l.Variable, l.Warning
+
+		f.Lines = append(f.Lines, n)
+	}
+
+	return f
+}
+
+// NewLineNode create new line from text (i.e. "greenadexchange.com, XF7342, DIRECT # comment"), to be inserted into
+// the file. Variables of new line are not validated against the file other variables
+func NewLineNode(text string) *LineNode {
+	n := newLineNode(text)
+	n.parse()
+	return n
+}
+
+// newLineNode split line text into its whitespace, content and comment
+func newLineNode(text string) *LineNode {
+	n := &LineNode{}
+
+	rest := strings.TrimLeftFunc(text, unicode.IsSpace)
+	n.Leading = text[:len(text)-len(rest)]
+
+	if i := strings.Index(rest, commentDenote); i != -1 {
+		rest, n.Comment = rest[:i], rest[i:]
+	}
+
+	n.Content = strings.TrimRightFunc(rest, unicode.IsSpace)
+	n.Trailing = rest[len(n.Content):]
+	return n
+}
+
+// parse the line content into Data\Variable record
+func (n *LineNode) parse() {
+	txt, _ := decodeLine(n.Content)
+	l := newRecords().parseLine(0, txt)
+	n.DataRecord, n.Variable, n.Warning = l.DataRecord, l.Variable, l.Warning
+}
+
+// Text return the line text, without line ending
+func (n *LineNode) Text() string {
+	return n.Leading + n.Content + n.Trailing + n.Comment
+}
+
+// SetContent replace the line content and parse it, keeping the line leading whitespace and comment
+func (n *LineNode) SetContent(content string) {
+	// separate the new content from comment of comment line
+	if len(n.Content) == 0 && len(n.Trailing) == 0 && len(n.Comment) > 0 {
+		n.Trailing = " "
+	}
+	n.Content = strings.TrimSpace(content)
+	n.parse()
+}
+
+// SetDataRecord replace the line content with data record in canonical form
+func (n *LineNode) SetDataRecord(r *DataRecord) {
+	n.SetContent(formatDataRecord(r))
+}
+
+// SetVariable replace the line content with variable in canonical form
+func (n *LineNode) SetVariable(v *Variable) {
+	n.SetContent(formatVariable(v))
+}
+
+// eol return the line ending used by the file
+func (f *File) eol() string {
+	for _, n := range f.Lines {
+		if len(n.EOL) > 0 {
+			return n.EOL
+		}
+	}
+	return "\n"
+}
+
+// Insert line at index (0 to insert at the top of the file, len(f.Lines) to append it)
+func (f *File) Insert(index int, n *LineNode) {
+	n.EOL = f.eol()
+	if index == len(f.Lines) && index > 0 && len(f.Lines[index-1].EOL) == 0 {
+		// keep the file without newline at its end
+		f.Lines[index-1].EOL, n.EOL = n.EOL, ""
+	}
+
+	f.Lines = append(f.Lines, nil)
+	copy(f.Lines[index+1:], f.Lines[index:])
+	f.Lines[index] = n
+}
+
+// Append line at the end of the file
+func (f *File) Append(n *LineNode) {
+	f.Insert(len(f.Lines), n)
+}
+
+// Remove the line at index
+func (f *File) Remove(index int) {
+	f.Lines = append(f.Lines[:index], f.Lines[index+1:]...)
+}
+
+// WriteTo write the file to w
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	if f.BOM {
+		b.Write(utf8BOM)
+	}
+	for _, n := range f.Lines {
+		b.WriteString(n.Text())
+		b.WriteString(n.EOL)
+	}
+	return b.WriteTo(w)
+}
+
+// Bytes return the file content
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	f.WriteTo(&b)
+	return b.Bytes()
+}
+
+// Records parse the file current content into Records, the same as ParseBody
+func (f *File) Records() (*Records, error) {
+	return ParseBody(f.Bytes())
+}
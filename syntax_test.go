@@ -0,0 +1,69 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseFileLossless test that parsed file is written back byte-identical
+func TestParseFileLossless(t *testing.T) {
+	bodies := []string{
+		"",
+		"google.com, pub-1234, DIRECT",
+		"\xef\xbb\xbf  google.com ,pub-1234,  DIRECT\t# comment  \r\n\r\n# just a comment\r\nCONTACT=adops@example.com\n",
+		"invalid line\rgreenadexchange.com, XF7342, RESELLER  \n  \n",
+	}
+
+	for _, body := range bodies {
+		f := ParseFile([]byte(body))
+		if string(f.Bytes()) != body {
+			t.Errorf("Expected file [%q] to be written byte-identical and not [%q]", body, f.Bytes())
+		}
+	}
+
+	f := ParseFile([]byte(bodies[2]))
+	if !f.BOM || len(f.Lines) != 4 {
+		t.Fatalf("Expected file with BOM and [4] lines and not [%t] [%d]", f.BOM, len(f.Lines))
+	}
+	n := f.Lines[0]
+	if n.Leading != "  " || n.Content != "google.com ,pub-1234,  DIRECT" || n.Trailing != "\t" || n.Comment != "# comment  " || n.EOL != "\r\n" {
+		t.Errorf("Expected line trivia to be split and not [%+v]", n)
+	}
+	if n.DataRecord == nil || n.DataRecord.PublisherAccountID != "pub-1234" {
+		t.Errorf("Expected line to be parsed into data record and not [%+v]", n.DataRecord)
+	}
+	if f.Lines[3].Variable == nil || f.Lines[3].Variable.Value != "adops@example.com" {
+		t.Errorf("Expected line to be parsed into variable and not [%+v]", f.Lines[3].Variable)
+	}
+}
+
+// TestFileEdit test editing file keeps the lines that were not modified
+func TestFileEdit(t *testing.T) {
+	body := "# ads.txt\r\n  google.com ,pub-1234,  DIRECT # google\r\nappnexus.com, 1234, RESELLER\r\nCONTACT=adops@example.com"
+	f := ParseFile([]byte(body))
+
+	f.Lines[1].SetDataRecord(&DataRecord{AdverterDomain: "google.com", PublisherAccountID: "pub-1234", AccountType: "RESELLER"})
+	f.Remove(2)
+	f.Insert(1, NewLineNode("greenadexchange.com, XF7342, DIRECT"))
+	f.Append(NewLineNode("OWNERDOMAIN=example.com"))
+	f.Lines[0].SetContent("CONTACT=ads@example.com")
+
+	expected := strings.Join([]string{
+		"CONTACT=ads@example.com # ads.txt",
+		"greenadexchange.com, XF7342, DIRECT",
+		"  google.com, pub-1234, RESELLER # google",
+		"CONTACT=adops@example.com",
+		"OWNERDOMAIN=example.com",
+	}, "\r\n")
+	if string(f.Bytes()) != expected {
+		t.Errorf("Expected edited file [%q] and not [%q]", expected, f.Bytes())
+	}
+
+	rec, err := f.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.DataRecords) != 2 || len(rec.Variables) != 3 || rec.DataRecords[1].AccountType != "RESELLER" {
+		t.Errorf("Expected edited file records and not [%s]", rec)
+	}
+}
@@ -0,0 +1,63 @@
+package adstxt
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Hop holds single HTTP request sent while fetching a file: the first request, and each redirect that was followed
+type Hop struct {
+	URL         string  `json:"url"`                // URL requested
+	StatusCode  int     `json:"statusCode"`         // StatusCode of the remote host response, or 0 if no response was received
+	Location    string  `json:"location,omitempty"` // Location header of redirect response
+	OutOfDomain bool    `json:"outOfDomain"`        // OutOfDomain true if the redirect location is outside the request root domain
+	Timing      *Timing `json:"timing"`             // Timing breakdown of the request (of the last attempt, if retried)
+}
+
+// Timing breakdown of single HTTP request. DNS, Connect and TLS are zero when a pooled connection was reused
+type Timing struct {
+	DNS     time.Duration `json:"dns"`     // DNS lookup duration
+	Connect time.Duration `json:"connect"` // Connect TCP connection duration
+	TLS     time.Duration `json:"tls"`     // TLS handshake duration
+	TTFB    time.Duration `json:"ttfb"`    // TTFB time from sending the request until the first response byte
+	Total   time.Duration `json:"total"`   // Total time from sending the request until the response headers were read
+
+	mu                                   sync.Mutex
+	start, dnsStart, connStart, tlsStart time.Time
+}
+
+// trace return context that record the request timing using httptrace
+func (t *Timing) trace(ctx context.Context) context.Context {
+	t.start = time.Now()
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.since(&t.DNS, &t.dnsStart) },
+		ConnectStart:         func(string, string) { t.mark(&t.connStart) },
+		ConnectDone:          func(string, string, error) { t.since(&t.Connect, &t.connStart) },
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.since(&t.TLS, &t.tlsStart) },
+		GotFirstResponseByte: func() { t.since(&t.TTFB, &t.start) },
+	})
+}
+
+// mark record the current time
+func (t *Timing) mark(at *time.Time) {
+	t.mu.Lock()
+	*at = time.Now()
+	t.mu.Unlock()
+}
+
+// since record the duration passed since the start time
+func (t *Timing) since(d *time.Duration, start *time.Time) {
+	t.mu.Lock()
+	*d = time.Since(*start)
+	t.mu.Unlock()
+}
+
+// done record the request total duration
+func (t *Timing) done() {
+	t.since(&t.Total, &t.start)
+}
@@ -0,0 +1,209 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SellersSource interface is used to load the sellers.json file of an advertising system, either from a local copy of the
+// file or by fetching it from the advertising system domain
+type SellersSource interface {
+	Sellers(adSystemDomain string) (*Sellers, error)
+}
+
+// A SellersSourceFunc is a function signature that implements the SellersSource interface
+type SellersSourceFunc func(adSystemDomain string) (*Sellers, error)
+
+// Sellers is the SellersSource interface implementation for the SellersSourceFunc type
+func (f SellersSourceFunc) Sellers(adSystemDomain string) (*Sellers, error) {
+	return f(adSystemDomain)
+}
+
+// SellersMap is a SellersSource of sellers.json files already loaded (i.e. parsed from local files), keyed by advertising
+// system domain
+type SellersMap map[string]*Sellers
+
+// Sellers is the SellersSource interface implementation for the SellersMap type
+func (m SellersMap) Sellers(adSystemDomain string) (*Sellers, error) {
+	s, ok := m[strings.ToLower(adSystemDomain)]
+	if !ok {
+		return nil, fmt.Errorf("sellers.json file for [%s] is not available", adSystemDomain)
+	}
+	return s, nil
+}
+
+// RemoteSellers is a SellersSource that fetch sellers.json file from advertising system domain. Each file is fetched once
+// and kept in memory, so it is safe to use for validating many Ads.txt files concurrently. Failure to fetch a file is not
+// kept, and the file is fetched again on the next call
+type RemoteSellers struct {
+	c       *Crawler
+	mu      sync.Mutex
+	sellers map[string]*remoteSellers
+}
+
+// remoteSellers result of fetching sellers.json file from advertising system domain
+type remoteSellers struct {
+	once    sync.Once
+	sellers *Sellers
+	err     error
+}
+
+// NewRemoteSellers create new SellersSource that fetch sellers.json files from advertising system domains using the
+// crawler settings (nil crawler use the same defaults as the package level functions)
+func NewRemoteSellers(c *Crawler) *RemoteSellers {
+	if c == nil {
+		c = defaultCrawler
+	}
+	return &RemoteSellers{c: c, sellers: map[string]*remoteSellers{}}
+}
+
+// Sellers is the SellersSource interface implementation for the RemoteSellers type
+func (r *RemoteSellers) Sellers(adSystemDomain string) (*Sellers, error) {
+	d := strings.ToLower(adSystemDomain)
+
+	r.mu.Lock()
+	rs, ok := r.sellers[d]
+	if !ok {
+		rs = &remoteSellers{}
+		r.sellers[d] = rs
+	}
+	r.mu.Unlock()
+
+	// fetch sellers.json file once per advertising system domain
+	rs.once.Do(func() {
+		req, err := NewSellersRequest(d)
+		if err != nil {
+			rs.err = err
+			return
+		}
+		res, err := r.c.GetSellers(req)
+		if err != nil {
+			rs.err = err
+			return
+		}
+		rs.sellers = res.Sellers
+	})
+
+	// do not keep failed fetch, so the next call will fetch the file again
+	if rs.err != nil {
+		r.mu.Lock()
+		if r.sellers[d] == rs {
+			delete(r.sellers, d)
+		}
+		r.mu.Unlock()
+	}
+
+	return rs.sellers, rs.err
+}
+
+// RecordFinding holds the result of validating single Ads.txt DataRecord against the sellers.json file of its advertising system
+type RecordFinding struct {
+	Record   *DataRecord `json:"record"`           // Record the validated Ads.txt DataRecord
+	Seller   *Seller     `json:"seller,omitempty"` // Seller matching the record publisher account ID (if found)
+	Warnings []*Warning  `json:"warnings"`         // Warnings for mismatched relationship between the record and the seller
+}
+
+// Valid return true if no mismatch was found between the DataRecord and the sellers.json file
+func (f *RecordFinding) Valid() bool {
+	return len(f.Warnings) == 0
+}
+
+// ValidateSellers validate each Ads.txt DataRecord of the specified publisher root domain against the sellers.json file of
+// the record advertising system: the publisher account ID should be listed in sellers.json, DIRECT records should match
+// PUBLISHER (or BOTH) sellers with the publisher root domain, and RESELLER records should match INTERMEDIARY (or BOTH) sellers
+func ValidateSellers(domain string, rec *Records, src SellersSource) []*RecordFinding {
+	findings := make([]*RecordFinding, len(rec.DataRecords))
+
+	// index sellers by seller ID, once per sellers.json file
+	indexes := map[*Sellers]map[string]*Seller{}
+
+	for i, r := range rec.DataRecords {
+		f := &RecordFinding{Record: r, Warnings: []*Warning{}}
+		findings[i] = f
+
+		sellers, err := src.Sellers(r.AdverterDomain)
+		if err == nil && sellers == nil {
+			err = fmt.Errorf("sellers.json file is not available")
+		}
+		if err != nil {
+			f.Warnings = append(f.Warnings, &Warning{
+				Level:   HighSeverity,
+				Message: fmt.Sprintf("Failed to load sellers.json file of %s [%s]", r.AdverterDomain, err.Error()),
+			})
+			continue
+		}
+
+		index, ok := indexes[sellers]
+		if !ok {
+			index = sellers.index()
+			indexes[sellers] = index
+		}
+
+		f.Seller = index[r.PublisherAccountID]
+		if f.Seller == nil {
+			f.Warnings = append(f.Warnings, &Warning{
+				Level:   HighSeverity,
+				Message: fmt.Sprintf("Publisher account ID %s is not listed in sellers.json file of %s", r.PublisherAccountID, r.AdverterDomain),
+			})
+			continue
+		}
+
+		if w := validateSellerRelationship(domain, r, f.Seller); w != nil {
+			f.Warnings = append(f.Warnings, w...)
+		}
+	}
+
+	return findings
+}
+
+// validateSellerRelationship compare DataRecord account type and publisher domain to the matching sellers.json seller
+func validateSellerRelationship(domain string, r *DataRecord, s *Seller) []*Warning {
+	var warnings []*Warning
+
+	switch r.AccountType {
+	case accountTypeDirect:
+		if s.SellerType != SellerTypePublisher && s.SellerType != SellerTypeBoth {
+			warnings = append(warnings, &Warning{
+				Level: HighSeverity,
+				Message: fmt.Sprintf("%s account type does not match seller type %s, expected seller type [%s] or [%s]",
+					accountTypeDirect, s.SellerType, SellerTypePublisher, SellerTypeBoth),
+			})
+		}
+
+		// the seller domain of a DIRECT relationship is the publisher own domain
+		if s.IsConfidential || len(s.Domain) == 0 {
+			warnings = append(warnings, &Warning{
+				Level:   LowSeverity,
+				Message: fmt.Sprintf("Seller %s domain is not disclosed, could not verify it matches publisher domain %s", s.SellerID, domain),
+			})
+			break
+		}
+		sellerDomain, err := rootDomain(s.Domain)
+		if err != nil || !strings.EqualFold(sellerDomain, domain) {
+			warnings = append(warnings, &Warning{
+				Level:   HighSeverity,
+				Message: fmt.Sprintf("Seller %s domain %s does not match publisher domain %s", s.SellerID, s.Domain, domain),
+			})
+		}
+	case accountTypeReseller:
+		if s.SellerType != SellerTypeIntermediary && s.SellerType != SellerTypeBoth {
+			warnings = append(warnings, &Warning{
+				Level: HighSeverity,
+				Message: fmt.Sprintf("%s account type does not match seller type %s, expected seller type [%s] or [%s]",
+					accountTypeReseller, s.SellerType, SellerTypeIntermediary, SellerTypeBoth),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// index return sellers keyed by seller ID
+func (s *Sellers) index() map[string]*Seller {
+	index := make(map[string]*Seller, len(s.Sellers))
+	for _, seller := range s.Sellers {
+		index[seller.SellerID] = seller
+	}
+	return index
+}
@@ -0,0 +1,108 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestValidateSellers test validating Ads.txt DataRecords against advertising system sellers.json file
+func TestValidateSellers(t *testing.T) {
+	sellers, err := ParseSellers([]byte(`{"version": "1.0", "sellers": [
+		{"seller_id": "XF7342", "seller_type": "PUBLISHER", "name": "Example", "domain": "www.example.com"},
+		{"seller_id": "XF7343", "seller_type": "INTERMEDIARY", "name": "Reseller", "domain": "reseller.com"},
+		{"seller_id": "XF7344", "seller_type": "PUBLISHER", "name": "Other", "domain": "other.com"},
+		{"seller_id": "XF7345", "seller_type": "BOTH", "is_confidential": 1}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := ParseBody([]byte(`greenadexchange.com, XF7342, DIRECT
+greenadexchange.com, XF7343, RESELLER
+greenadexchange.com, XF7343, DIRECT
+greenadexchange.com, XF7344, DIRECT
+greenadexchange.com, XF7345, RESELLER
+greenadexchange.com, XF0000, DIRECT
+testexchange.net, XF7342, DIRECT`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := ValidateSellers("example.com", rec, SellersMap{"greenadexchange.com": sellers})
+	if len(findings) != len(rec.DataRecords) {
+		t.Fatalf("Expected finding for each of [%d] DataRecords but received [%d]", len(rec.DataRecords), len(findings))
+	}
+
+	// expected number of warnings for each record
+	expected := []int{0, 0, 2, 1, 0, 1, 1}
+	for i, e := range expected {
+		if len(findings[i].Warnings) != e {
+			t.Errorf("Expected [%d] warnings for record [%v] but received %d", e, findings[i].Record, len(findings[i].Warnings))
+		}
+	}
+
+	if findings[0].Seller == nil || findings[0].Seller.SellerID != "XF7342" {
+		t.Errorf("Expected finding to include matching seller [XF7342] and not [%v]", findings[0].Seller)
+	}
+
+	if !findings[1].Valid() {
+		t.Errorf("Expected RESELLER record to match INTERMEDIARY seller")
+	}
+}
+
+// TestValidateSellersNilSellers test sellers source that return no sellers.json file and no error is reported as failure
+// to load the file
+func TestValidateSellersNilSellers(t *testing.T) {
+	rec, err := ParseBody([]byte("greenadexchange.com, XF7342, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := SellersSourceFunc(func(adSystemDomain string) (*Sellers, error) {
+		return nil, nil
+	})
+
+	findings := ValidateSellers("example.com", rec, src)
+	if len(findings) != 1 || len(findings[0].Warnings) != 1 || findings[0].Warnings[0].Level != HighSeverity {
+		t.Fatalf("Expected single high severity warning for record with no sellers.json file and not [%v]", findings)
+	}
+	if findings[0].Seller != nil {
+		t.Errorf("Expected no matching seller and not [%v]", findings[0].Seller)
+	}
+}
+
+// TestRemoteSellers test fetching sellers.json file using the crawler settings, and that failure to fetch the file is not kept
+func TestRemoteSellers(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "remote-sellers-test" {
+			t.Errorf("Expected sellers.json request to use crawler user agent and not [%s]", r.Header.Get("User-Agent"))
+		}
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"version": "1.0", "sellers": [{"seller_id": "XF7342", "seller_type": "PUBLISHER", "domain": "example.com"}]}`)
+	}))
+	defer ts.Close()
+
+	src := NewRemoteSellers(NewCrawler(WithUserAgent("remote-sellers-test")))
+
+	if _, err := src.Sellers(ts.URL); err == nil {
+		t.Errorf("Expected error fetching sellers.json file from [%s]", ts.URL)
+	}
+
+	s, err := src.Sellers(ts.URL)
+	if err != nil || len(s.Sellers) != 1 {
+		t.Fatalf("Expected failed fetch to be retried and single seller but found [%v] [%v]", s, err)
+	}
+
+	// successful fetch is kept
+	if _, err := src.Sellers(ts.URL); err != nil || atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("Expected sellers.json file to be fetched [2] times and not [%d]", atomic.LoadInt32(&requests))
+	}
+}
@@ -2,10 +2,12 @@ package adstxt
 
 // Warning represent failure to parse Ads.txt line according to official ads.txt spec
 type Warning struct {
-	Index   int      `json:"index"` // Index of the line in the Ads.txt file in which warning was found
-	Text    string   `json:"txt"`   // Text of the line in the Ads.txt file in which warning was found
-	Message string   `json:"msg"`   // Warning reason
-	Level   Severity `json:"level"` // Severity level of parse warning
+	Index   int      `json:"index"`           // Index of the line in the Ads.txt file in which warning was found
+	Text    string   `json:"txt"`             // Text of the line in the Ads.txt file in which warning was found
+	Message string   `json:"msg"`             // Warning reason
+	Level   Severity `json:"level"`           // Severity level of parse warning
+	Fix     *Fix     `json:"fix,omitempty"`   // Fix suggested fix of the line, if it can be fixed mechanically
+	Lines   []int    `json:"lines,omitempty"` // Lines all the lines involved in file level warning (i.e. duplicate records)
 }
 
 // Severity of parse warning (low for moderate warning, high indicates potential error)